@@ -0,0 +1,204 @@
+package sego
+
+import (
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf16"
+)
+
+// 与Elasticsearch IK分析器对应的分词模式
+type AnalyzeMode int
+
+const (
+	// 对应ik_smart，输出精确模式的分词
+	AnalyzeSmart AnalyzeMode = iota
+
+	// 对应ik_max_word，输出词典中所有能匹配的分词，以及没有被这些分词覆盖的单字
+	AnalyzeMaxWord
+)
+
+// 返回分析器名对应的分词模式，支持"ik_smart"和"ik_max_word"
+func ParseAnalyzeMode(analyzer string) (AnalyzeMode, error) {
+	switch analyzer {
+	case "ik_smart":
+		return AnalyzeSmart, nil
+	case "ik_max_word":
+		return AnalyzeMaxWord, nil
+	}
+	return 0, fmt.Errorf("未知的分析器 \"%s\"", analyzer)
+}
+
+// Elasticsearch _analyze接口输出的一个分词
+//
+// 起止位置与Elasticsearch相同，以UTF-16编码单元计算，对BMP以外的字符与
+// Segment的Start()和End()不同。
+type AnalyzeToken struct {
+	Token       string `json:"token"`
+	StartOffset int    `json:"start_offset"`
+	EndOffset   int    `json:"end_offset"`
+	Type        string `json:"type"`
+	Position    int    `json:"position"`
+}
+
+// Elasticsearch _analyze接口的响应
+type AnalyzeResponse struct {
+	Tokens []AnalyzeToken `json:"tokens"`
+}
+
+// 按IK分析器的方式分析文本，输出与Elasticsearch _analyze接口相同
+//
+// 与IK相同，标点和空白不输出，分词的位置从0开始依次加一。ik_max_word模式下
+// 分词按起始位置排列，起始位置相同时较长的在前。
+func (seg *Segmenter) Analyze(bytes []byte, mode AnalyzeMode) AnalyzeResponse {
+	response := AnalyzeResponse{Tokens: make([]AnalyzeToken, 0)}
+	seg.analyze(&response, bytes, mode, 0, 0)
+	return response
+}
+
+// 分析多段文本，与Elasticsearch对数组形式的text相同，每段文本的位置比前一段
+// 文本结束时的位置增加positionGap，起止位置增加前一段文本的长度再加上offsetGap。
+// 没有输出分词的文本同样占用positionGap和offsetGap。
+func (seg *Segmenter) AnalyzeTexts(texts []string, mode AnalyzeMode, positionGap, offsetGap int) AnalyzeResponse {
+	response := AnalyzeResponse{Tokens: make([]AnalyzeToken, 0)}
+	position, offset := 0, 0
+	for i, text := range texts {
+		if i > 0 {
+			position += positionGap
+			offset += offsetGap
+		}
+		position = seg.analyze(&response, []byte(text), mode, position, offset)
+		offset += len(utf16.Encode([]rune(text)))
+	}
+	return response
+}
+
+// 分析一段文本并追加到response中，第一个分词的位置为position，返回下一个分词的位置
+func (seg *Segmenter) analyze(response *AnalyzeResponse, bytes []byte, mode AnalyzeMode, position, offset int) int {
+	segs := seg.Segment(bytes)
+	var cuts []CutAll
+	if mode == AnalyzeMaxWord {
+		cuts = seg.maxWordCuts(bytes, segs)
+	} else {
+		cuts = make([]CutAll, len(segs))
+		for i := range segs {
+			cuts[i] = CutAll{
+				Start: segs[i].start,
+				End:   segs[i].end,
+				Token: segs[i].token.Text(),
+				Pos:   segs[i].Pos(),
+			}
+		}
+	}
+
+	offsets := runeToUTF16Offsets(bytes)
+	for _, cut := range cuts {
+		tokenType := analyzeType(cut.Token, cut.Pos)
+		if tokenType == "" {
+			continue
+		}
+		response.Tokens = append(response.Tokens, AnalyzeToken{
+			Token:       cut.Token,
+			StartOffset: offset + offsets[minInt(cut.Start, len(offsets)-1)],
+			EndOffset:   offset + offsets[minInt(cut.End, len(offsets)-1)],
+			Type:        tokenType,
+			Position:    position,
+		})
+		position++
+	}
+	return position
+}
+
+// 返回词典中所有能匹配的分词，以及精确模式中没有被这些分词覆盖的分词
+func (seg *Segmenter) maxWordCuts(bytes []byte, segs []Segment) []CutAll {
+	text := splitTextToWords(bytes)
+	tokens := make([]*Token, seg.dict.maxTokenLength)
+	output := make([]CutAll, 0, len(segs))
+
+	// covered[i]为true表示第i个字已被多字元的分词覆盖
+	covered := make([]bool, textSliceByteLength(text))
+	start := 0
+	for current := range text {
+		numTokens := seg.dict.lookupTokens(
+			text[current:minInt(current+seg.dict.maxTokenLength, len(text))], tokens)
+		for i := 0; i < numTokens; i++ {
+			if len(tokens[i].text) < 2 {
+				continue
+			}
+			end := start + textSliceByteLength(tokens[i].text)
+			output = append(output, CutAll{Start: start, End: end, Token: tokens[i].Text(), Pos: tokens[i].Pos()})
+			for j := start; j < end; j++ {
+				covered[j] = true
+			}
+		}
+		start += textSliceByteLength(text[current : current+1])
+	}
+
+	for i := range segs {
+		if !covered[segs[i].start] || !covered[segs[i].end-1] {
+			output = append(output, CutAll{
+				Start: segs[i].start,
+				End:   segs[i].end,
+				Token: segs[i].token.Text(),
+				Pos:   segs[i].Pos(),
+			})
+		}
+	}
+
+	sort.SliceStable(output, func(i, j int) bool {
+		if output[i].Start != output[j].Start {
+			return output[i].Start < output[j].Start
+		}
+		return output[i].End > output[j].End
+	})
+	return output
+}
+
+// 返回IK分析器的分词类型，标点和空白返回空字符串
+func analyzeType(token, pos string) string {
+	var han, letters, digits, runes int
+	for _, r := range token {
+		runes++
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.IsLetter(r):
+			letters++
+		case unicode.IsDigit(r):
+			digits++
+		}
+	}
+	switch {
+	case han > 0 && pos != "" && pos[0] == 'm':
+		return "TYPE_CNUM"
+	case han > 0 && pos != "" && pos[0] == 'q':
+		return "COUNT"
+	case han > 0 && runes == 1:
+		return "CN_CHAR"
+	case han > 0:
+		return "CN_WORD"
+	case letters > 0 && digits == 0:
+		return "ENGLISH"
+	case digits > 0 && letters == 0:
+		return "ARABIC"
+	case letters > 0:
+		return "LETTER"
+	}
+	return ""
+}
+
+// 分词的位置以字计算，返回每个字在文本中的UTF-16位置，最后一项为文本的UTF-16长度
+func runeToUTF16Offsets(bytes []byte) []int {
+	offsets := make([]int, 0, len(bytes)+1)
+	offset := 0
+	for _, r := range string(bytes) {
+		offsets = append(offsets, offset)
+		if r >= 0x10000 {
+			// BMP以外的字符在UTF-16中占两个编码单元
+			offset += 2
+		} else {
+			offset++
+		}
+	}
+	return append(offsets, offset)
+}
@@ -0,0 +1,86 @@
+package sego
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func formatAnalyzeTokens(response AnalyzeResponse) []string {
+	output := make([]string, len(response.Tokens))
+	for i, token := range response.Tokens {
+		output[i] = fmt.Sprintf("%d-%d %s/%s@%d",
+			token.StartOffset, token.EndOffset, token.Token, token.Type, token.Position)
+	}
+	return output
+}
+
+func newTestAnalyzeSegmenter(t *testing.T) *Segmenter {
+	t.Helper()
+	seg := new(Segmenter)
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"中华 50 nz",
+		"华人 20 n",
+		"人民 100 n",
+		"共和国 60 n",
+		"中华人民共和国 200 ns",
+	))
+	return seg
+}
+
+func TestAnalyzeModes(t *testing.T) {
+	seg := newTestAnalyzeSegmenter(t)
+	text := []byte("中华人民共和国，abc 123")
+
+	smart := []string{
+		"0-7 中华人民共和国/CN_WORD@0",
+		"8-11 abc/ENGLISH@1",
+		"12-15 123/ARABIC@2",
+	}
+	if got := formatAnalyzeTokens(seg.Analyze(text, AnalyzeSmart)); !reflect.DeepEqual(got, smart) {
+		t.Errorf("Analyze(ik_smart) = %q, want %q", got, smart)
+	}
+
+	// 词典中所有能匹配的分词，按起始位置排列，起始位置相同时较长的在前
+	maxWord := []string{
+		"0-7 中华人民共和国/CN_WORD@0",
+		"0-2 中华/CN_WORD@1",
+		"1-3 华人/CN_WORD@2",
+		"2-4 人民/CN_WORD@3",
+		"4-7 共和国/CN_WORD@4",
+		"8-11 abc/ENGLISH@5",
+		"12-15 123/ARABIC@6",
+	}
+	if got := formatAnalyzeTokens(seg.Analyze(text, AnalyzeMaxWord)); !reflect.DeepEqual(got, maxWord) {
+		t.Errorf("Analyze(ik_max_word) = %q, want %q", got, maxWord)
+	}
+}
+
+func TestAnalyzeUTF16Offsets(t *testing.T) {
+	seg := newTestAnalyzeSegmenter(t)
+
+	// U+20000在UTF-16中占两个编码单元，之后的起止位置与分词的字位置不同
+	want := []string{"0-2 𠀀/CN_CHAR@0", "2-4 人民/CN_WORD@1"}
+	if got := formatAnalyzeTokens(seg.Analyze([]byte("𠀀人民"), AnalyzeSmart)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() = %q, want %q", got, want)
+	}
+	segs := seg.Segment([]byte("𠀀人民"))
+	if segs[1].Start() != 1 || segs[1].End() != 3 {
+		t.Errorf("Segment() 人民 at %d-%d, want 1-3", segs[1].Start(), segs[1].End())
+	}
+}
+
+func TestAnalyzeTexts(t *testing.T) {
+	seg := newTestAnalyzeSegmenter(t)
+
+	// 没有分词的第二段文本同样占用位置间隔和偏移间隔
+	got := formatAnalyzeTokens(seg.AnalyzeTexts([]string{"𠀀人民", "，", "共和国"}, AnalyzeSmart, 100, 1))
+	want := []string{
+		"0-2 𠀀/CN_CHAR@0",
+		"2-4 人民/CN_WORD@1",
+		"7-10 共和国/CN_WORD@202",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AnalyzeTexts() = %q, want %q", got, want)
+	}
+}
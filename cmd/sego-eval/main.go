@@ -0,0 +1,61 @@
+// sego分词准确率评测工具
+//
+// 用法与SIGHAN Bakeoff的评分脚本类似，比如
+//	sego-eval -dict=dictionary.txt -vocab=pku_training_words.utf8 pku_test_gold.utf8
+// 输出准确率、召回率、F1、未登录词召回率、登录词召回率以及错误最多的句子。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/eval"
+)
+
+var (
+	dictFiles  = flag.String("dict", "", "词典文件，多个文件用\",\"分隔")
+	vocabFiles = flag.String("vocab", "", "判断未登录词的词表文件，每行一个分词，为空时使用词典")
+	worst      = flag.Int("worst", 10, "列出的错误最多的句子数")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法：%s [选项] 标准答案文件\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *dictFiles == "" || flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	log.SetOutput(io.Discard)
+	var segmenter sego.Segmenter
+	if err := segmenter.LoadDictionaryE(*dictFiles); err != nil {
+		fatalf("%s", err)
+	}
+
+	evaluator := eval.NewEvaluator(&segmenter)
+	evaluator.SetWorst(*worst)
+	if *vocabFiles != "" {
+		if err := evaluator.LoadVocabularyFile(*vocabFiles); err != nil {
+			fatalf("%s", err)
+		}
+	}
+	report, err := evaluator.EvaluateFile(flag.Arg(0))
+	if err != nil {
+		fatalf("%s", err)
+	}
+	if _, err := report.WriteTo(os.Stdout); err != nil {
+		fatalf("%s", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
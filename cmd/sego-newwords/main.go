@@ -0,0 +1,74 @@
+// sego新词发现工具
+//
+// 从未分词的文本中发现词典中没有的新词，输出LoadDictionary格式的候选词，比如
+//	sego-newwords -dict=dictionary.txt -top=100 领域文本.txt > 候选词.txt
+// 没有指定文件时从标准输入读入。指标的含义见newwords包的说明。
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/newwords"
+)
+
+var (
+	dictFiles  = flag.String("dict", "", "用于过滤已有分词的词典文件，多个文件用\",\"分隔")
+	maxLength  = flag.Int("max-length", 4, "候选词的最大字数")
+	minCount   = flag.Int("min-count", 5, "候选词的最少出现次数")
+	minPMI     = flag.Float64("min-pmi", 3, "凝固度的阈值")
+	minEntropy = flag.Float64("min-entropy", 1, "自由度的阈值")
+	pos        = flag.String("pos", "n", "输出的候选词的词性")
+	top        = flag.Int("top", 0, "最多输出的候选词数，为0时全部输出")
+)
+
+func main() {
+	flag.Parse()
+
+	discoverer := newwords.NewDiscoverer()
+	discoverer.SetMaxLength(*maxLength)
+	discoverer.SetMinCount(*minCount)
+	discoverer.SetMinPMI(*minPMI)
+	discoverer.SetMinEntropy(*minEntropy)
+
+	if flag.NArg() == 0 {
+		if err := discoverer.Scan(os.Stdin); err != nil {
+			fatalf("%s", err)
+		}
+	} else if err := discoverer.ScanFile(strings.Join(flag.Args(), ",")); err != nil {
+		fatalf("%s", err)
+	}
+
+	var dict *sego.Dictionary
+	if *dictFiles != "" {
+		log.SetOutput(io.Discard)
+		var segmenter sego.Segmenter
+		if err := segmenter.LoadDictionaryE(*dictFiles); err != nil {
+			fatalf("%s", err)
+		}
+		dict = segmenter.Dictionary()
+	}
+
+	candidates := discoverer.Candidates(dict)
+	if *top > 0 && len(candidates) > *top {
+		candidates = candidates[:*top]
+	}
+	writer := bufio.NewWriter(os.Stdout)
+	if err := newwords.WriteCandidates(writer, candidates, *pos); err != nil {
+		fatalf("%s", err)
+	}
+	if err := writer.Flush(); err != nil {
+		fatalf("%s", err)
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
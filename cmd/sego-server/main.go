@@ -0,0 +1,58 @@
+// sego分词HTTP服务
+//
+// 接口见server包的说明，比如
+//	sego-server -dict=dictionary.txt -addr=:8080
+//	curl -d '{"text": "中华人民共和国"}' localhost:8080/segment
+// 收到SIGHUP时重新载入词典，与请求/reload相同。
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/crossgit/sego/server"
+)
+
+var (
+	addr         = flag.String("addr", ":8080", "监听地址")
+	dictFiles    = flag.String("dict", "", "词典文件，多个文件用\",\"分隔")
+	stopFiles    = flag.String("stop", "", "停用词文件，多个文件用\",\"分隔")
+	idfFiles     = flag.String("idf", "", "关键词提取使用的IDF表文件，多个文件用\",\"分隔")
+	maxBodyBytes = flag.Int64("max-body", 1<<20, "请求体的最大字节数")
+	maxBatchSize = flag.Int("max-batch", 100, "批量请求中文本的最大个数")
+)
+
+func main() {
+	flag.Parse()
+	if *dictFiles == "" {
+		log.Fatal("需要用-dict指定词典文件")
+	}
+
+	s, err := server.New(server.Options{
+		Dictionary:   *dictFiles,
+		StopWords:    *stopFiles,
+		IDF:          *idfFiles,
+		MaxBodyBytes: *maxBodyBytes,
+		MaxBatchSize: *maxBatchSize,
+	})
+	if err != nil {
+		log.Fatalf("无法启动服务: %s", err)
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			if err := s.Reload(); err != nil {
+				log.Printf("重新载入词典失败: %s", err)
+			}
+		}
+	}()
+
+	log.Printf("sego服务监听 %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, s))
+}
@@ -0,0 +1,85 @@
+// sego词频训练工具
+//
+// 从分好词的语料中统计词频，与已有词典合并后写出新的词典，比如
+//	sego-train -dict=dictionary.txt -corpus=领域语料.txt -out=领域词典.txt
+// 语料格式见sego.FrequencyTrainer的说明。
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/crossgit/sego"
+)
+
+var (
+	dictFiles    = flag.String("dict", "", "合并的词典文件，多个文件用\",\"分隔，为空时只使用语料词频")
+	corpusFiles  = flag.String("corpus", "", "分好词的语料文件，多个文件用\",\"分隔")
+	output       = flag.String("out", "", "输出的词典文件，为空时写到标准输出")
+	dictWeight   = flag.Float64("dict-weight", 0.5, "词典词频的权重")
+	corpusWeight = flag.Float64("corpus-weight", 0.5, "语料词频的权重")
+	normalize    = flag.Bool("normalize", true, "按词典总词频归一化语料词频")
+	unknownPos   = flag.String("unknown-pos", "", "语料中不带词性、词典中也没有的分词的词性，为空时不带词性")
+)
+
+func main() {
+	flag.Parse()
+	if *corpusFiles == "" {
+		fmt.Fprintln(os.Stderr, "需要用-corpus指定语料文件")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	trainer := sego.NewFrequencyTrainer()
+	if err := trainer.TrainFile(*corpusFiles); err != nil {
+		fatalf("%s", err)
+	}
+
+	var dict *sego.Dictionary
+	if *dictFiles != "" {
+		log.SetOutput(io.Discard)
+		var segmenter sego.Segmenter
+		if err := segmenter.LoadDictionaryE(*dictFiles); err != nil {
+			fatalf("%s", err)
+		}
+		dict = segmenter.Dictionary()
+	}
+
+	var file *os.File
+	writer := io.Writer(os.Stdout)
+	if *output != "" {
+		var err error
+		if file, err = os.Create(*output); err != nil {
+			fatalf("%s", err)
+		}
+		writer = file
+	}
+	buf := bufio.NewWriter(writer)
+	weights := sego.FrequencyWeights{
+		DictionaryWeight: *dictWeight,
+		CorpusWeight:     *corpusWeight,
+		Normalize:        *normalize,
+		UnknownPos:       *unknownPos,
+	}
+	if err := trainer.WriteDictionary(buf, dict, weights); err != nil {
+		fatalf("%s", err)
+	}
+	if err := buf.Flush(); err != nil {
+		fatalf("%s", err)
+	}
+	// fatalf直接退出，不能用defer关闭文件；关闭时的写入错误也要报告
+	if file != nil {
+		if err := file.Close(); err != nil {
+			fatalf("%s", err)
+		}
+	}
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
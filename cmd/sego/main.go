@@ -0,0 +1,174 @@
+// sego命令行分词工具
+//
+// 从标准输入或文件逐行读入文本，分词后写到标准输出，比如
+//	sego -dict=用户词典.txt,通用词典.txt -format=pos 输入.txt
+//	echo "中华人民共和国" | sego -dict=dictionary.txt -search
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/crossgit/sego"
+)
+
+var (
+	dictFiles   = flag.String("dict", "", "词典文件，多个文件用\",\"分隔，与Segmenter.LoadDictionary相同")
+	format      = flag.String("format", "plain", "输出格式：plain、pos、full、json、conll或corpus")
+	searchMode  = flag.Bool("search", false, "搜索模式，与SegmentsToString的searchMode相同")
+	stopFiles   = flag.String("stop", "", "停用词文件，多个文件用\",\"分隔，设置后输出中去掉停用词")
+	punctuation = flag.String("punct", "", "标点的词性，比如w，为空时标点的词性为x")
+	whitespace  = flag.String("space", "keep", "空白的处理方式：keep、drop或merge")
+	quiet       = flag.Bool("quiet", true, "不输出载入词典的日志")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "用法：%s [选项] [文件...]\n没有指定文件时从标准输入读入。\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *dictFiles == "" {
+		fmt.Fprintln(os.Stderr, "需要用-dict指定词典文件")
+		flag.Usage()
+		os.Exit(2)
+	}
+	switch *format {
+	case "plain", "pos", "full", "json", "conll", "corpus":
+	default:
+		fatalf("未知的输出格式 \"%s\"", *format)
+	}
+	policy := sego.SymbolPolicy{PunctuationPos: *punctuation}
+	switch *whitespace {
+	case "keep":
+		policy.Whitespace = sego.KeepWhitespace
+	case "drop":
+		policy.Whitespace = sego.DropWhitespace
+	case "merge":
+		policy.Whitespace = sego.MergeWhitespace
+	default:
+		fatalf("未知的空白处理方式 \"%s\"", *whitespace)
+	}
+	if *quiet {
+		log.SetOutput(io.Discard)
+	}
+
+	var segmenter sego.Segmenter
+	if err := segmenter.LoadDictionaryE(*dictFiles); err != nil {
+		fatalf("%s", err)
+	}
+	if *stopFiles != "" {
+		if err := segmenter.LoadStopWordsE(*stopFiles); err != nil {
+			fatalf("%s", err)
+		}
+	}
+	segmenter.SetSymbolPolicy(policy)
+
+	// 出错时先写出已处理部分的结果再退出
+	writer := bufio.NewWriter(os.Stdout)
+	err := run(&segmenter, writer)
+	if flushErr := writer.Flush(); err == nil {
+		err = flushErr
+	}
+	if err != nil {
+		fatalf("%s", err)
+	}
+}
+
+// 依次处理命令行中的文件，没有指定文件时处理标准输入
+func run(segmenter *sego.Segmenter, output io.Writer) error {
+	if flag.NArg() == 0 {
+		return process(segmenter, os.Stdin, output)
+	}
+	for _, file := range flag.Args() {
+		input, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = process(segmenter, input, output)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %s", file, err)
+		}
+	}
+	return nil
+}
+
+// 逐行分词并按格式输出
+func process(segmenter *sego.Segmenter, input io.Reader, output io.Writer) error {
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if err := writeLine(segmenter, line, output); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func writeLine(segmenter *sego.Segmenter, line []byte, output io.Writer) error {
+	if *format == "full" {
+		return writeFullCut(segmenter.SegmentAll(line), output)
+	}
+
+	segs := segmenter.Segment(line)
+	if *stopFiles != "" {
+		segs = segmenter.FilterStopWords(segs)
+	}
+
+	switch *format {
+	case "plain":
+		var words []string
+		if *stopFiles != "" {
+			// 搜索模式下子分词中的停用词也要去掉
+			words = segmenter.SegmentsToSliceFiltered(segs, *searchMode)
+		} else {
+			words = sego.SegmentsToSlice(segs, *searchMode)
+		}
+		_, err := fmt.Fprintln(output, strings.Join(words, " "))
+		return err
+	case "pos":
+		if err := sego.WriteSegments(output, segs, *searchMode); err != nil {
+			return err
+		}
+		_, err := io.WriteString(output, "\n")
+		return err
+	case "json":
+		if *searchMode {
+			return json.NewEncoder(output).Encode(sego.SearchTerms(segs))
+		}
+		return sego.WriteJSONLine(output, segs)
+	case "conll":
+		return sego.WriteCoNLLU(output, segs)
+	case "corpus":
+		return sego.WriteCorpus(output, segs)
+	}
+	return fmt.Errorf("未知的输出格式 \"%s\"", *format)
+}
+
+// 输出全切结果，每个分词为"分词/词性 "，与SegmentAll的结果一一对应
+func writeFullCut(cuts []sego.CutAll, output io.Writer) error {
+	var line strings.Builder
+	for _, cut := range cuts {
+		line.WriteString(cut.Token)
+		line.WriteByte('/')
+		line.WriteString(cut.Pos)
+		line.WriteByte(' ')
+	}
+	line.WriteByte('\n')
+	_, err := io.WriteString(output, line.String())
+	return err
+}
+
+func fatalf(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
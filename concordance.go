@@ -0,0 +1,187 @@
+package sego
+
+import "strings"
+
+// 上下文窗口的计量单位
+type ContextUnit int
+
+const (
+	// 以分词为单位，与SegmentsOutputSingle的左右边距相同
+	ContextTokens ContextUnit = iota
+
+	// 以字为单位
+	ContextChars
+
+	// 以句子为单位，0表示只取关键词所在句子的部分，1表示再向外扩展一句，依此类推
+	ContextSentences
+)
+
+// 关键词上下文（KWIC）检索的选项
+type ConcordanceOptions struct {
+	// 上下文窗口的计量单位
+	Unit ContextUnit
+
+	// 左右上下文的大小
+	Left  int
+	Right int
+}
+
+// 一篇已分词的文档
+type ConcordanceDocument struct {
+	// 文档标识，原样填入检索结果
+	ID string
+
+	// 文档原文，按字或句子计算上下文时使用；为空时由分词结果拼接得到，
+	// 此时被SymbolPolicy丢弃的空白不会出现在上下文中
+	Text []byte
+
+	// 文档的分词结果
+	Segments []Segment
+}
+
+// 一条检索结果
+type ConcordanceHit struct {
+	// 文档标识
+	DocID string
+
+	// 命中的分词及其在分词结果中的序号
+	Segment Segment
+	Index   int
+
+	// 命中分词的文本和起止位置，位置与Segment的Start()和End()一致
+	Keyword string
+	Start   int
+	End     int
+
+	// 左右上下文
+	Left  string
+	Right string
+}
+
+// 返回匹配给定文本的分词判断函数
+func MatchWords(words ...string) func(*Segment) bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[strings.ToLower(word)] = true
+	}
+	return func(s *Segment) bool {
+		return set[s.token.Text()]
+	}
+}
+
+// 返回匹配给定词性的分词判断函数
+func MatchPos(pos ...string) func(*Segment) bool {
+	set := make(map[string]bool, len(pos))
+	for _, p := range pos {
+		set[p] = true
+	}
+	return func(s *Segment) bool {
+		return set[s.Pos()]
+	}
+}
+
+// 返回匹配标注集中某一词性类别的分词判断函数
+func (ts *TagSet) MatchCategory(category TagCategory) func(*Segment) bool {
+	return func(s *Segment) bool {
+		return ts.Is(s.Pos(), category)
+	}
+}
+
+// 在一篇文档中检索所有满足match的分词及其上下文
+func Concordance(doc ConcordanceDocument, match func(*Segment) bool, options ConcordanceOptions) []ConcordanceHit {
+	output := make([]ConcordanceHit, 0)
+	segs := doc.Segments
+
+	// 没有原文时由分词结果拼接，offsets为每个分词在拼接结果中的起始位置
+	var runes []rune
+	var offsets []int
+	if options.Unit != ContextTokens {
+		if doc.Text != nil {
+			runes = []rune(string(doc.Text))
+		} else {
+			offsets = make([]int, len(segs))
+			for i := range segs {
+				offsets[i] = len(runes)
+				runes = append(runes, []rune(segs[i].token.Text())...)
+			}
+		}
+	}
+
+	for i := range segs {
+		if !match(&segs[i]) {
+			continue
+		}
+		hit := ConcordanceHit{
+			DocID:   doc.ID,
+			Segment: segs[i],
+			Index:   i,
+			Keyword: segs[i].token.Text(),
+			Start:   segs[i].start,
+			End:     segs[i].end,
+		}
+		if options.Unit == ContextTokens {
+			hit.Left = segmentArrayToStr(segs[maxInt(0, i-options.Left):i])
+			hit.Right = segmentArrayToStr(segs[i+1 : minInt(len(segs), i+1+options.Right)])
+			output = append(output, hit)
+			continue
+		}
+
+		start, end := hit.Start, hit.End
+		if offsets != nil {
+			start = offsets[i]
+			end = start + len([]rune(hit.Keyword))
+		}
+		start, end = minInt(start, len(runes)), minInt(end, len(runes))
+		switch options.Unit {
+		case ContextChars:
+			hit.Left = string(runes[maxInt(0, start-options.Left):start])
+			hit.Right = string(runes[end:minInt(len(runes), end+options.Right)])
+		case ContextSentences:
+			hit.Left = string(runes[sentenceStart(runes, start, options.Left):start])
+			hit.Right = string(runes[end:sentenceEnd(runes, end, options.Right)])
+		}
+		output = append(output, hit)
+	}
+	return output
+}
+
+// 在多篇文档中检索，结果按文档顺序排列
+func ConcordanceBatch(docs []ConcordanceDocument, match func(*Segment) bool, options ConcordanceOptions) []ConcordanceHit {
+	output := make([]ConcordanceHit, 0)
+	for _, doc := range docs {
+		output = append(output, Concordance(doc, match, options)...)
+	}
+	return output
+}
+
+// 判断字符是否为句子的结束标点
+func isSentenceEnd(r rune) bool {
+	return strings.ContainsRune("。！？!?；;…\n", r)
+}
+
+// 从pos向前找到第n+1个句子的开头，n为0时返回pos所在句子的开头
+func sentenceStart(runes []rune, pos, n int) int {
+	for i := pos - 1; i >= 0; i-- {
+		// 连续的结束标点属于同一个句子
+		if isSentenceEnd(runes[i]) && (i+1 == pos || !isSentenceEnd(runes[i+1])) {
+			if n == 0 {
+				return i + 1
+			}
+			n--
+		}
+	}
+	return 0
+}
+
+// 从pos向后找到第n+1个句子的结尾（包括结束标点），n为0时返回pos所在句子的结尾
+func sentenceEnd(runes []rune, pos, n int) int {
+	for i := pos; i < len(runes); i++ {
+		if isSentenceEnd(runes[i]) && (i+1 == len(runes) || !isSentenceEnd(runes[i+1])) {
+			if n == 0 {
+				return i + 1
+			}
+			n--
+		}
+	}
+	return len(runes)
+}
@@ -0,0 +1,49 @@
+package sego
+
+import "testing"
+
+func TestConcordanceContext(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"中国 100 ns",
+		"人民 100 n",
+		"万岁 100 v",
+	))
+	seg.SetSymbolPolicy(SymbolPolicy{Whitespace: DropWhitespace})
+
+	text := []byte("中国   人民 万岁。人民")
+	segs := seg.Segment(text)
+	cases := []struct {
+		name    string
+		doc     ConcordanceDocument
+		options ConcordanceOptions
+		left    string
+		right   string
+	}{
+		{"tokens", ConcordanceDocument{Segments: segs},
+			ConcordanceOptions{Unit: ContextTokens, Left: 1, Right: 1}, "中国", "万岁"},
+		{"chars with text", ConcordanceDocument{Text: text, Segments: segs},
+			ConcordanceOptions{Unit: ContextChars, Left: 2, Right: 2}, "  ", " 万"},
+		// 没有原文时上下文由分词拼接，位置仍对应原文
+		{"chars without text", ConcordanceDocument{Segments: segs},
+			ConcordanceOptions{Unit: ContextChars, Left: 2, Right: 2}, "中国", "万岁"},
+		{"sentences with text", ConcordanceDocument{Text: text, Segments: segs},
+			ConcordanceOptions{Unit: ContextSentences}, "中国   ", " 万岁。"},
+		{"sentences without text", ConcordanceDocument{Segments: segs},
+			ConcordanceOptions{Unit: ContextSentences}, "中国", "万岁。"},
+	}
+	for _, c := range cases {
+		hits := Concordance(c.doc, MatchWords("人民"), c.options)
+		if len(hits) != 2 {
+			t.Errorf("%s: got %d hits, want 2", c.name, len(hits))
+			continue
+		}
+		hit := hits[0]
+		if hit.Start != 5 || hit.End != 7 || hit.Index != 1 {
+			t.Errorf("%s: hit at %d-%d index %d, want 5-7 index 1", c.name, hit.Start, hit.End, hit.Index)
+		}
+		if hit.Left != c.left || hit.Right != c.right {
+			t.Errorf("%s: context %q %q, want %q %q", c.name, hit.Left, hit.Right, c.left, c.right)
+		}
+	}
+}
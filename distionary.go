@@ -4,10 +4,11 @@ import "github.com/adamzy/cedar-go"
 
 // Dictionary结构体实现了一个字串前缀树，一个分词可能出现在叶子节点也有可能出现在非叶节点
 type Dictionary struct {
-	trie           *cedar.Cedar // Cedar 前缀树
-	maxTokenLength int          // 词典中最长的分词
-	tokens         []Token      // 词典中所有的分词，方便遍历
-	totalFrequency int64        // 词典中所有分词的频率之和
+	trie           *cedar.Cedar     // Cedar 前缀树
+	maxTokenLength int              // 词典中最长的分词
+	tokens         []Token          // 词典中所有的分词，方便遍历
+	totalFrequency int64            // 词典中所有分词的频率之和
+	pinyinIndex    map[string][]int // 全拼和拼音首字母到分词序号的索引
 }
 
 func NewDictionary() *Dictionary {
@@ -29,11 +30,22 @@ func (dict *Dictionary) TotalFrequency() int64 {
 	return dict.totalFrequency
 }
 
-// 向词典中加入一个分词
+// 返回词典中所有的分词，顺序与载入顺序相同
+func (dict *Dictionary) Tokens() []*Token {
+	tokens := make([]*Token, len(dict.tokens))
+	for i := range dict.tokens {
+		tokens[i] = &dict.tokens[i]
+	}
+	return tokens
+}
+
+// 向词典中加入一个分词，分词已存在时将新的词性追加到已有分词上
 func (dict *Dictionary) addToken(token Token) {
 	bytes := textSliceToBytes(token.text)
-	_, err := dict.trie.Get(bytes)
+	value, err := dict.trie.Get(bytes)
 	if err == nil {
+		// 已有的分词优先，只追加新的词性
+		dict.tokens[value].mergeTags(token.tags)
 		return
 	}
 
@@ -63,3 +75,22 @@ func (dict *Dictionary) lookupTokens(words []Text, tokens []*Token) (numOfTokens
 	}
 	return
 }
+
+// 追加分词中没有的词性，词频和主词性保持不变
+func (token *Token) mergeTags(tags []TokenPos) {
+	for _, tag := range tags {
+		if !token.HasPos(tag.Pos) {
+			token.tags = append(token.PosTags(), tag)
+		}
+	}
+}
+
+// 在词典中查找分词，找不到时返回nil
+func (dict *Dictionary) Lookup(text string) *Token {
+	bytes := textSliceToBytes(splitTextToWords([]byte(text)))
+	value, err := dict.trie.Get(bytes)
+	if err != nil {
+		return nil
+	}
+	return &dict.tokens[value]
+}
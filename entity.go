@@ -0,0 +1,318 @@
+package sego
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// 命名实体类型
+type EntityType int
+
+const (
+	// 人名
+	EntityPerson EntityType = iota
+
+	// 地名
+	EntityPlace
+
+	// 机构名
+	EntityOrganization
+)
+
+func (t EntityType) String() string {
+	switch t {
+	case EntityPerson:
+		return "person"
+	case EntityPlace:
+		return "place"
+	case EntityOrganization:
+		return "organization"
+	}
+	return "unknown"
+}
+
+// 分词结果中识别出的一个命名实体
+type Entity struct {
+	// 实体类型
+	Type EntityType
+
+	// 实体文本
+	Text string
+
+	// 实体在文本中的起止位置，与Segment的Start()和End()一致
+	Start int
+	End   int
+
+	// 组成该实体的分词，是输入分词结果的子切片
+	Segments []Segment
+}
+
+// 命名实体识别器
+//
+// 识别器在Segment的分词结果上工作，按以下规则识别实体：
+//	1. 词典中标注为人名、地名、机构名的分词直接作为实体，这些词性由标注集的AddEntity声明
+//	2. 机构名：以"公司"、"大学"、"局"等后缀结尾，向前合并名词性分词
+//	3. 地名：以"省"、"市"、"县"等后缀结尾，向前合并名词性分词
+//	4. 人名：姓氏分词后接一到两个单字，单字不能是常见的虚词
+// 后缀规则可以吸收规则1得到的实体，比如"北京/ns 大学/n"合并为机构名；
+// 除此之外前面的规则优先，已被识别为实体的分词不再参与后面的规则。
+// 识别器默认使用DefaultTagSet，分词器使用其它标注集时应调用SetTagSet(seg.TagSet())。
+type EntityRecognizer struct {
+	tags                 *TagSet
+	surnames             map[string]bool
+	givenNameChars       map[string]bool // 为空时接受除虚词外的任意单字
+	excludedChars        map[string]bool
+	placeSuffixes        []string
+	organizationSuffixes []string
+	entityPos            map[string]EntityType // SetEntityPos设置的词性，优先于标注集
+
+	// 后缀之前最多合并的分词数
+	maxPlacePrefix        int
+	maxOrganizationPrefix int
+}
+
+// 新建一个使用默认姓氏表和后缀表的识别器
+func NewEntityRecognizer() *EntityRecognizer {
+	r := &EntityRecognizer{
+		tags:                  DefaultTagSet,
+		surnames:              make(map[string]bool),
+		givenNameChars:        make(map[string]bool),
+		excludedChars:         make(map[string]bool),
+		entityPos:             make(map[string]EntityType),
+		maxPlacePrefix:        3,
+		maxOrganizationPrefix: 4,
+	}
+	r.AddSurnames(defaultSurnames...)
+	for _, char := range strings.Split(defaultExcludedNameChars, "") {
+		r.excludedChars[char] = true
+	}
+	r.AddPlaceSuffixes(defaultPlaceSuffixes...)
+	r.AddOrganizationSuffixes(defaultOrganizationSuffixes...)
+	return r
+}
+
+// 设置判断词性使用的标注集，通常为分词器的TagSet()
+func (r *EntityRecognizer) SetTagSet(ts *TagSet) {
+	r.tags = ts
+}
+
+// 添加姓氏，可以是复姓
+func (r *EntityRecognizer) AddSurnames(surnames ...string) {
+	for _, surname := range surnames {
+		r.surnames[surname] = true
+	}
+}
+
+// 添加名字用字，添加后人名规则只接受这些字作为名字
+func (r *EntityRecognizer) AddGivenNameChars(chars string) {
+	for _, char := range strings.Split(chars, "") {
+		r.givenNameChars[char] = true
+	}
+}
+
+// 添加地名后缀
+func (r *EntityRecognizer) AddPlaceSuffixes(suffixes ...string) {
+	r.placeSuffixes = appendSuffixes(r.placeSuffixes, suffixes)
+}
+
+// 添加机构名后缀
+func (r *EntityRecognizer) AddOrganizationSuffixes(suffixes ...string) {
+	r.organizationSuffixes = appendSuffixes(r.organizationSuffixes, suffixes)
+}
+
+// 设置词典中可以直接作为实体的词性，优先于标注集中的声明
+func (r *EntityRecognizer) SetEntityPos(pos string, t EntityType) {
+	r.entityPos[pos] = t
+}
+
+// 返回词性表示的实体类型
+func (r *EntityRecognizer) entityType(pos string) (EntityType, bool) {
+	if t, ok := r.entityPos[pos]; ok {
+		return t, true
+	}
+	return r.tags.Entity(pos)
+}
+
+// 追加后缀并按长度从长到短排列，保证优先匹配最长后缀
+func appendSuffixes(suffixes []string, added []string) []string {
+	suffixes = append(suffixes, added...)
+	sort.SliceStable(suffixes, func(i, j int) bool {
+		return len(suffixes[i]) > len(suffixes[j])
+	})
+	return suffixes
+}
+
+// 识别分词结果中的命名实体，返回的实体按起始位置排列且互不重叠
+func (r *EntityRecognizer) Recognize(segs []Segment) []Entity {
+	covered := make([]bool, len(segs))
+	output := make([]Entity, 0)
+	add := func(t EntityType, first, last int) {
+		entity := newEntity(t, segs[first:last+1])
+		// 去掉被新实体吸收的实体
+		kept := output[:0]
+		for _, e := range output {
+			if e.Start < entity.Start || e.End > entity.End {
+				kept = append(kept, e)
+			}
+		}
+		output = append(kept, entity)
+		for i := first; i <= last; i++ {
+			covered[i] = true
+		}
+	}
+
+	// 词典中的实体词性
+	for i := range segs {
+		if t, ok := r.entityType(segs[i].Pos()); ok {
+			add(t, i, i)
+		}
+	}
+
+	// 机构名和地名，机构名优先
+	for i := range segs {
+		if covered[i] {
+			continue
+		}
+		first, ok := r.matchSuffix(segs, i, r.organizationSuffixes, r.maxOrganizationPrefix)
+		if ok {
+			add(EntityOrganization, first, i)
+		} else if first, ok = r.matchSuffix(segs, i, r.placeSuffixes, r.maxPlacePrefix); ok {
+			add(EntityPlace, first, i)
+		}
+	}
+
+	// 人名
+	for i := 0; i < len(segs); i++ {
+		surnameEnd, ok := r.matchSurname(segs, covered, i)
+		if !ok {
+			continue
+		}
+		last := surnameEnd
+		for j := surnameEnd + 1; j < len(segs) && j <= surnameEnd+2 && !covered[j]; j++ {
+			if !r.isGivenNameChar(&segs[j]) {
+				break
+			}
+			last = j
+		}
+		if last > surnameEnd {
+			add(EntityPerson, i, last)
+			i = last
+		}
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Start < output[j].Start
+	})
+	return output
+}
+
+// 判断第i个分词是否以后缀结尾，是则向前合并名词性分词并返回实体的第一个分词
+func (r *EntityRecognizer) matchSuffix(
+	segs []Segment, i int, suffixes []string, maxPrefix int) (int, bool) {
+	text := segs[i].token.Text()
+	for _, suffix := range suffixes {
+		if !strings.HasSuffix(text, suffix) {
+			continue
+		}
+		// 单字后缀必须独立成词，避免把"全部"、"大海"这样的普通词当作实体
+		if len(text) > len(suffix) && utf8.RuneCountInString(suffix) == 1 {
+			continue
+		}
+		first := i
+		for j := i - 1; j >= 0 && i-j <= maxPrefix; j-- {
+			if !r.isNameLike(&segs[j]) {
+				break
+			}
+			first = j
+		}
+		// 分词本身就是后缀时必须至少合并一个分词
+		if first < i || len(text) > len(suffix) {
+			return first, true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// 判断第i个分词起是否为姓氏，复姓可能被切分为两个单字，返回姓氏的最后一个分词
+func (r *EntityRecognizer) matchSurname(segs []Segment, covered []bool, i int) (int, bool) {
+	if covered[i] {
+		return 0, false
+	}
+	text := segs[i].token.Text()
+	if i+1 < len(segs) && !covered[i+1] && r.surnames[text+segs[i+1].token.Text()] {
+		return i + 1, true
+	}
+	return i, r.surnames[text]
+}
+
+// 判断分词能否作为实体名称的一部分：名词、简称或虚词以外的未登录字元
+func (r *EntityRecognizer) isNameLike(s *Segment) bool {
+	if symbolKind(s.token) != symbolNone {
+		return false
+	}
+	pos := s.Pos()
+	if r.tags.Is(pos, TagUnknown) {
+		return !r.excludedChars[s.token.Text()]
+	}
+	if _, ok := r.entityType(pos); ok || r.tags.Is(pos, TagNoun) {
+		return true
+	}
+	return strings.HasPrefix(pos, "n") || pos == "j"
+}
+
+// 判断分词能否作为名字用字
+func (r *EntityRecognizer) isGivenNameChar(s *Segment) bool {
+	text := s.token.Text()
+	if utf8.RuneCountInString(text) != 1 || symbolKind(s.token) != symbolNone {
+		return false
+	}
+	if r.excludedChars[text] {
+		return false
+	}
+	if len(r.givenNameChars) > 0 {
+		return r.givenNameChars[text]
+	}
+	// 没有名字用字表时只接受未登录的汉字和名词性单字
+	r0, _ := utf8.DecodeRuneInString(text)
+	pos := s.Pos()
+	return r0 >= 0x4e00 && r0 <= 0x9fff &&
+		(r.tags.Is(pos, TagUnknown) || r.tags.Is(pos, TagNoun) || strings.HasPrefix(pos, "n"))
+}
+
+func newEntity(t EntityType, segs []Segment) Entity {
+	return Entity{
+		Type:     t,
+		Text:     segmentArrayToStr(segs),
+		Start:    segs[0].start,
+		End:      segs[len(segs)-1].end,
+		Segments: segs,
+	}
+}
+
+var defaultSurnames = []string{
+	"王", "李", "张", "刘", "陈", "杨", "黄", "赵", "吴", "周", "徐", "孙", "马", "朱",
+	"胡", "郭", "何", "高", "林", "罗", "郑", "梁", "谢", "宋", "唐", "许", "韩", "冯",
+	"邓", "曹", "彭", "曾", "肖", "田", "董", "袁", "潘", "于", "蒋", "蔡", "余", "杜",
+	"叶", "程", "苏", "魏", "吕", "丁", "任", "沈", "姚", "卢", "姜", "崔", "钟", "谭",
+	"陆", "汪", "范", "金", "石", "廖", "贾", "夏", "韦", "付", "方", "白", "邹", "孟",
+	"熊", "秦", "邱", "江", "尹", "薛", "闫", "段", "雷", "侯", "龙", "史", "陶", "黎",
+	"贺", "顾", "毛", "郝", "龚", "邵", "万", "钱", "严", "覃", "武", "戴", "莫", "孔",
+	"向", "汤", "欧阳", "司马", "上官", "诸葛", "东方", "皇甫", "尉迟", "公孙", "慕容",
+	"长孙", "宇文", "司徒", "夏侯", "轩辕", "令狐", "端木", "西门", "南宫", "独孤",
+}
+
+// 不会出现在名字中的常见虚词和高频字
+const defaultExcludedNameChars = "的了在是和与及或也都就而被把将对从向于为以之这那有不个们着过吗呢吧啊说到给让"
+
+var defaultPlaceSuffixes = []string{
+	"省", "市", "县", "区", "镇", "乡", "村", "州", "旗", "盟", "街道", "路", "街", "巷",
+	"山", "河", "江", "湖", "海", "岛", "湾", "港", "自治区", "自治州", "自治县",
+}
+
+var defaultOrganizationSuffixes = []string{
+	"公司", "集团", "大学", "学院", "学校", "中学", "小学", "医院", "银行", "研究所",
+	"研究院", "研究中心", "中心", "协会", "学会", "委员会", "基金会", "政府", "法院",
+	"检察院", "局", "厅", "部", "署", "社", "厂", "报社", "电视台",
+}
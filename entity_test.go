@@ -0,0 +1,74 @@
+package sego
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func formatEntities(entities []Entity) []string {
+	output := make([]string, len(entities))
+	for i, e := range entities {
+		output[i] = fmt.Sprintf("%d-%d %s/%s", e.Start, e.End, e.Text, e.Type)
+	}
+	return output
+}
+
+func TestEntityRecognizer(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"北京 100 ns",
+		"大学 100 n",
+		"读书 100 v",
+		"家住 100 v",
+		"海淀 100 n",
+		"区 100 n",
+	))
+	r := NewEntityRecognizer()
+
+	cases := []struct {
+		text string
+		want []string
+	}{
+		// 姓氏后接未登录的单字，"在"不能作为名字
+		{"王小明在北京大学读书", []string{"0-3 王小明/person", "4-8 北京大学/organization"}},
+		{"家住海淀区", []string{"2-5 海淀区/place"}},
+		{"北京", []string{"0-2 北京/place"}},
+		// 复姓
+		{"欧阳明读书", []string{"0-3 欧阳明/person"}},
+		{"王在读书", []string{}},
+	}
+	for _, c := range cases {
+		got := formatEntities(r.Recognize(seg.Segment([]byte(c.text))))
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Recognize(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestEntityRecognizerTagSet(t *testing.T) {
+	ts := NewTagSet("custom",
+		Tag{"v", "动词", TagOther},
+		Tag{"nh", "人名", TagNoun},
+		Tag{"x", "未登录字元", TagUnknown},
+	)
+	ts.AddEntity("nh", EntityPerson)
+
+	var seg Segmenter
+	seg.SetTagSet(ts)
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"李雷 100 nh",
+		"读书 100 v",
+	))
+	segs := seg.Segment([]byte("李雷读书"))
+
+	r := NewEntityRecognizer()
+	if got := r.Recognize(segs); len(got) != 0 {
+		t.Errorf("Recognize() with default tag set = %q, want none", formatEntities(got))
+	}
+	r.SetTagSet(seg.TagSet())
+	want := []string{"0-2 李雷/person"}
+	if got := formatEntities(r.Recognize(segs)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Recognize() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,280 @@
+// 分词准确率评测，与SIGHAN Bakeoff（icwb2）的评分方法相同
+//
+// 标准答案每行一个句子，分词之间用空白分隔。评测时把每行的分词拼接成原文交给
+// Segmenter分词，按分词在原文中的起止位置与标准答案比较，位置完全相同才算正确。
+package eval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/crossgit/sego"
+)
+
+// 评测结果
+type Report struct {
+	// 句子数
+	Sentences int
+
+	// 标准答案的分词数、分词结果的分词数和正确的分词数
+	GoldWords    int
+	TestWords    int
+	CorrectWords int
+
+	// 准确率、召回率和F1值
+	Precision float64
+	Recall    float64
+	F1        float64
+
+	// 未登录词（不在词表中的标准答案分词）的个数及其召回率
+	OOVWords  int
+	OOVRate   float64
+	OOVRecall float64
+
+	// 登录词的召回率
+	IVRecall float64
+
+	// 错误最多的句子，按错误数从多到少排列
+	Worst []SentenceDiff
+}
+
+// 一个句子的评测结果
+type SentenceDiff struct {
+	// 句子在标准答案中的行号，从1开始
+	Line int
+
+	// 标准答案和分词结果
+	Gold []string
+	Test []string
+
+	// 错误数，即标准答案中没有被正确切出的分词数与分词结果中错误的分词数之和
+	Errors int
+}
+
+// 评测器
+type Evaluator struct {
+	segmenter  *sego.Segmenter
+	vocabulary map[string]bool
+	worst      int
+}
+
+// 新建评测segmenter的评测器
+//
+// 没有载入词表时以segmenter的词典为词表判断未登录词；与icwb2相同，也可以用
+// LoadVocabulary载入训练语料的词表。
+func NewEvaluator(segmenter *sego.Segmenter) *Evaluator {
+	return &Evaluator{segmenter: segmenter, worst: 10}
+}
+
+// 设置报告中列出的错误最多的句子数，默认为10
+func (e *Evaluator) SetWorst(n int) {
+	e.worst = n
+}
+
+// 从reader中载入词表，每行一个分词
+func (e *Evaluator) LoadVocabulary(reader io.Reader) error {
+	if e.vocabulary == nil {
+		e.vocabulary = make(map[string]bool)
+	}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			e.vocabulary[strings.ToLower(word)] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// 从文件中载入词表，多个文件名用","分隔
+func (e *Evaluator) LoadVocabularyFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		vocabularyFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = e.LoadVocabulary(vocabularyFile)
+		vocabularyFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Evaluator) inVocabulary(word string) bool {
+	word = strings.ToLower(word)
+	if e.vocabulary != nil {
+		return e.vocabulary[word]
+	}
+	return e.segmenter.Dictionary().Lookup(word) != nil
+}
+
+// 分词的起止位置，以字计算
+type span struct {
+	start, end int
+}
+
+// 评测标准答案gold，空行被忽略
+func (e *Evaluator) Evaluate(gold io.Reader) (*Report, error) {
+	report := &Report{}
+	var oovCorrect, ivCorrect int
+	diffs := make([]SentenceDiff, 0)
+
+	scanner := bufio.NewScanner(gold)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		goldWords := strings.FieldsFunc(scanner.Text(), unicode.IsSpace)
+		if len(goldWords) == 0 {
+			continue
+		}
+		report.Sentences++
+
+		goldSpans := make(map[span]bool, len(goldWords))
+		start := 0
+		for _, word := range goldWords {
+			end := start + utf8.RuneCountInString(word)
+			goldSpans[span{start, end}] = true
+			start = end
+		}
+
+		segs := e.segmenter.Segment([]byte(strings.Join(goldWords, "")))
+		testWords := make([]string, 0, len(segs))
+		correct := make(map[span]bool)
+		for i := range segs {
+			s := span{segs[i].Start(), segs[i].End()}
+			testWords = append(testWords, segs[i].Token().Text())
+			if goldSpans[s] {
+				correct[s] = true
+			}
+		}
+
+		start = 0
+		for _, word := range goldWords {
+			end := start + utf8.RuneCountInString(word)
+			hit := correct[span{start, end}]
+			if e.inVocabulary(word) {
+				if hit {
+					ivCorrect++
+				}
+			} else {
+				report.OOVWords++
+				if hit {
+					oovCorrect++
+				}
+			}
+			start = end
+		}
+
+		report.GoldWords += len(goldWords)
+		report.TestWords += len(testWords)
+		report.CorrectWords += len(correct)
+		if numErrors := len(goldWords) + len(testWords) - 2*len(correct); numErrors > 0 {
+			diffs = append(diffs, SentenceDiff{Line: line, Gold: goldWords, Test: testWords, Errors: numErrors})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	report.Precision = ratio(report.CorrectWords, report.TestWords)
+	report.Recall = ratio(report.CorrectWords, report.GoldWords)
+	if report.Precision+report.Recall > 0 {
+		report.F1 = 2 * report.Precision * report.Recall / (report.Precision + report.Recall)
+	}
+	report.OOVRate = ratio(report.OOVWords, report.GoldWords)
+	report.OOVRecall = ratio(oovCorrect, report.OOVWords)
+	report.IVRecall = ratio(ivCorrect, report.GoldWords-report.OOVWords)
+
+	sort.SliceStable(diffs, func(i, j int) bool {
+		return diffs[i].Errors > diffs[j].Errors
+	})
+	if len(diffs) > e.worst {
+		diffs = diffs[:e.worst]
+	}
+	report.Worst = diffs
+	return report, nil
+}
+
+// 评测文件中的标准答案
+func (e *Evaluator) EvaluateFile(file string) (*Report, error) {
+	goldFile, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer goldFile.Close()
+	return e.Evaluate(goldFile)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// 把评测结果写成文本报告，错误最多的句子中与标准答案不同的分词用[]标出
+func (report *Report) WriteTo(w io.Writer) (int64, error) {
+	var output strings.Builder
+	fmt.Fprintf(&output, "句子数\t%d\n", report.Sentences)
+	fmt.Fprintf(&output, "标准分词数\t%d\n", report.GoldWords)
+	fmt.Fprintf(&output, "切分词数\t%d\n", report.TestWords)
+	fmt.Fprintf(&output, "正确词数\t%d\n", report.CorrectWords)
+	fmt.Fprintf(&output, "准确率\t%.4f\n", report.Precision)
+	fmt.Fprintf(&output, "召回率\t%.4f\n", report.Recall)
+	fmt.Fprintf(&output, "F1\t%.4f\n", report.F1)
+	fmt.Fprintf(&output, "未登录词率\t%.4f\n", report.OOVRate)
+	fmt.Fprintf(&output, "未登录词召回率\t%.4f\n", report.OOVRecall)
+	fmt.Fprintf(&output, "登录词召回率\t%.4f\n", report.IVRecall)
+
+	for _, diff := range report.Worst {
+		fmt.Fprintf(&output, "\n第%d行，%d处错误\n", diff.Line, diff.Errors)
+		gold, test := markDiff(diff.Gold, diff.Test)
+		fmt.Fprintf(&output, "标准\t%s\n", gold)
+		fmt.Fprintf(&output, "切分\t%s\n", test)
+	}
+
+	n, err := io.WriteString(w, output.String())
+	return int64(n), err
+}
+
+// 用空格连接两种切分，并用[]标出另一种切分中没有的分词
+func markDiff(gold, test []string) (string, string) {
+	goldSpans := wordSpans(gold)
+	testSpans := wordSpans(test)
+	return joinMarked(gold, goldSpans, testSpans), joinMarked(test, testSpans, goldSpans)
+}
+
+func wordSpans(words []string) []span {
+	spans := make([]span, len(words))
+	start := 0
+	for i, word := range words {
+		end := start + utf8.RuneCountInString(word)
+		spans[i] = span{start, end}
+		start = end
+	}
+	return spans
+}
+
+func joinMarked(words []string, spans, other []span) string {
+	set := make(map[span]bool, len(other))
+	for _, s := range other {
+		set[s] = true
+	}
+	marked := make([]string, len(words))
+	for i, word := range words {
+		if set[spans[i]] {
+			marked[i] = word
+		} else {
+			marked[i] = "[" + word + "]"
+		}
+	}
+	return strings.Join(marked, " ")
+}
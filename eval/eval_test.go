@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crossgit/sego"
+)
+
+const testDictionary = `中国 100 ns
+人民 100 n
+万岁 100 v
+经济 100 n
+发展 100 v
+`
+
+// 空行被忽略但仍计入行号
+const testGold = `中国 人民 万岁
+
+经济发展 很 快
+人民 发 展
+万岁 万岁
+鑫
+`
+
+func newTestEvaluator(t *testing.T) *Evaluator {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(file, []byte(testDictionary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+	return NewEvaluator(segmenter)
+}
+
+func TestEvaluate(t *testing.T) {
+	evaluator := newTestEvaluator(t)
+	if err := evaluator.LoadVocabulary(strings.NewReader("中国\n人民\n万岁\n经济\n发展\n很\n快\n")); err != nil {
+		t.Fatal(err)
+	}
+	report, err := evaluator.Evaluate(strings.NewReader(testGold))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 各行的标准分词数/切分词数/正确数：3/3/3、3/4/2、3/2/1、2/2/2、1/1/1
+	// 未登录词为"经济发展"、"发"、"展"和"鑫"，只有"鑫"被正确切出
+	if report.Sentences != 5 || report.GoldWords != 12 || report.TestWords != 12 || report.CorrectWords != 9 {
+		t.Errorf("Evaluate() counts = %d %d %d %d, want 5 12 12 9",
+			report.Sentences, report.GoldWords, report.TestWords, report.CorrectWords)
+	}
+	metrics := []struct {
+		name      string
+		got, want float64
+	}{
+		{"Precision", report.Precision, 9.0 / 12},
+		{"Recall", report.Recall, 9.0 / 12},
+		{"F1", report.F1, 9.0 / 12},
+		{"OOVRate", report.OOVRate, 4.0 / 12},
+		{"OOVRecall", report.OOVRecall, 1.0 / 4},
+		{"IVRecall", report.IVRecall, 8.0 / 8},
+	}
+	for _, m := range metrics {
+		if math.Abs(m.got-m.want) > 1e-9 {
+			t.Errorf("%s = %v, want %v", m.name, m.got, m.want)
+		}
+	}
+	if report.OOVWords != 4 {
+		t.Errorf("OOVWords = %d, want 4", report.OOVWords)
+	}
+
+	// 错误数为标准分词数加切分词数减去两倍正确数，相同时保持行的顺序
+	want := []SentenceDiff{
+		{Line: 3, Gold: []string{"经济发展", "很", "快"}, Test: []string{"经济", "发展", "很", "快"}, Errors: 3},
+		{Line: 4, Gold: []string{"人民", "发", "展"}, Test: []string{"人民", "发展"}, Errors: 3},
+	}
+	if !reflect.DeepEqual(report.Worst, want) {
+		t.Errorf("Worst = %v, want %v", report.Worst, want)
+	}
+
+	var output strings.Builder
+	if _, err := report.WriteTo(&output); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		"准确率\t0.7500\n",
+		"未登录词召回率\t0.2500\n",
+		"第3行，3处错误\n标准\t[经济发展] 很 快\n切分\t[经济] [发展] 很 快\n",
+		"第4行，3处错误\n标准\t人民 [发] [展]\n切分\t人民 [发展]\n",
+	} {
+		if !strings.Contains(output.String(), line) {
+			t.Errorf("WriteTo() output does not contain %q:\n%s", line, output.String())
+		}
+	}
+}
+
+func TestEvaluateDictionaryVocabulary(t *testing.T) {
+	evaluator := newTestEvaluator(t)
+	evaluator.SetWorst(1)
+	report, err := evaluator.Evaluate(strings.NewReader(testGold))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有词表时以词典判断，"很"和"快"也是未登录词
+	if report.OOVWords != 6 || math.Abs(report.OOVRecall-3.0/6) > 1e-9 {
+		t.Errorf("OOVWords = %d, OOVRecall = %v, want 6 and 0.5", report.OOVWords, report.OOVRecall)
+	}
+	if len(report.Worst) != 1 || report.Worst[0].Line != 3 {
+		t.Errorf("Worst = %v, want only line 3", report.Worst)
+	}
+}
@@ -0,0 +1,243 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 词频训练器，从分好词的语料中统计分词和词性的频率，与已有词典合并后写出
+// LoadDictionary格式的词典文件
+//
+// 语料每行一个句子，分词之间用空白分隔。分词可以带词性，写作"分词文本/词性"，
+// 格式与PosTagger.Train相同；不带词性的分词使用词典中的主词性，词典中也没有时
+// 使用FrequencyWeights.UnknownPos，默认不带词性。
+type FrequencyTrainer struct {
+	// 分词 -> 词性 -> 出现次数，不带词性的分词记在空字符串下
+	counts map[string]map[string]int
+
+	// 语料中的分词总数
+	total int64
+}
+
+// 词典词频与语料词频的合并方式
+//
+// 合并后分词每个词性的频率为
+//	DictionaryWeight * 词典频率 + CorpusWeight * scale * 语料频率
+// Normalize为true时scale为词典总词频与语料总词频之比，使两者的量级相同，否则为1。
+// DictionaryWeight大于0时，词典中已有的分词合并后的频率至少为LoadDictionary载入分词
+// 所需的最小频率，合并不会让词典丢失分词。
+type FrequencyWeights struct {
+	DictionaryWeight float64
+	CorpusWeight     float64
+	Normalize        bool
+
+	// 语料中不带词性、词典中也没有的分词使用的词性，为空时写出的词典中该分词不带词性。
+	// 注意"x"在DefaultTagSet中属于TagUnknown类别，会被按类别输出的函数过滤掉
+	UnknownPos string
+}
+
+// 返回默认的合并方式：词典和语料各占一半，语料词频按词典总词频归一化，
+// 无法确定词性的分词不带词性
+func DefaultFrequencyWeights() FrequencyWeights {
+	return FrequencyWeights{
+		DictionaryWeight: 0.5,
+		CorpusWeight:     0.5,
+		Normalize:        true,
+	}
+}
+
+// 新建一个空的词频训练器
+func NewFrequencyTrainer() *FrequencyTrainer {
+	return &FrequencyTrainer{counts: make(map[string]map[string]int)}
+}
+
+// 从语料中统计词频，可以多次调用以累加语料
+func (trainer *FrequencyTrainer) Train(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		for _, field := range strings.Fields(scanner.Text()) {
+			word, pos, ok := parseTaggedWord(field)
+			if !ok {
+				word, pos = strings.ToLower(field), ""
+			}
+			if trainer.counts[word] == nil {
+				trainer.counts[word] = make(map[string]int)
+			}
+			trainer.counts[word][pos]++
+			trainer.total++
+		}
+	}
+	return scanner.Err()
+}
+
+// 从文件中统计词频，多个文件名用","分隔
+func (trainer *FrequencyTrainer) TrainFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		corpusFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = trainer.Train(corpusFile)
+		corpusFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 词典文件中的一行
+type DictionaryEntry struct {
+	Text string
+
+	// 按频率从高到低排列，第一个为主词性
+	Tags []TokenPos
+}
+
+// 返回分词的总频率
+func (entry *DictionaryEntry) Frequency() (frequency int) {
+	for _, tag := range entry.Tags {
+		frequency += tag.Frequency
+	}
+	return
+}
+
+// 把语料词频与词典dict合并，dict为nil时只使用语料词频
+//
+// 结果按频率从高到低排列，频率相同时按分词文本排列。合并后频率为0的词性和分词被去掉，
+// 词典中已有的分词除外，见FrequencyWeights的注释。
+func (trainer *FrequencyTrainer) Entries(dict *Dictionary, weights FrequencyWeights) []DictionaryEntry {
+	merged := make(map[string]map[string]float64)
+	add := func(word, pos string, frequency float64) {
+		if merged[word] == nil {
+			merged[word] = make(map[string]float64)
+		}
+		merged[word][pos] += frequency
+	}
+
+	scale := 1.0
+	if dict != nil {
+		if weights.Normalize && trainer.total > 0 && dict.totalFrequency > 0 {
+			scale = float64(dict.totalFrequency) / float64(trainer.total)
+		}
+		for _, token := range dict.Tokens() {
+			for _, tag := range token.PosTags() {
+				add(token.Text(), tag.Pos, weights.DictionaryWeight*float64(tag.Frequency))
+			}
+		}
+	}
+
+	for word, counts := range trainer.counts {
+		for pos, count := range counts {
+			if pos == "" {
+				// 不带词性的分词使用词典中的主词性
+				pos = weights.UnknownPos
+				if dict != nil {
+					if token := dict.Lookup(word); token != nil && token.Pos() != "" {
+						pos = token.Pos()
+					}
+				}
+			}
+			add(word, pos, weights.CorpusWeight*scale*float64(count))
+		}
+	}
+
+	entries := make([]DictionaryEntry, 0, len(merged))
+	for word, tags := range merged {
+		entry := DictionaryEntry{Text: word}
+		for pos, frequency := range tags {
+			if f := int(math.Round(frequency)); f > 0 {
+				entry.Tags = append(entry.Tags, TokenPos{Pos: pos, Frequency: f})
+			}
+		}
+		sort.Slice(entry.Tags, func(i, j int) bool {
+			if entry.Tags[i].Frequency != entry.Tags[j].Frequency {
+				return entry.Tags[i].Frequency > entry.Tags[j].Frequency
+			}
+			return entry.Tags[i].Pos < entry.Tags[j].Pos
+		})
+
+		// 词典中已有的分词补足到最小频率，以免重新载入时被忽略
+		if dict != nil && weights.DictionaryWeight > 0 {
+			if token := dict.Lookup(word); token != nil {
+				if frequency := entry.Frequency(); frequency < minTokenFrequency {
+					if len(entry.Tags) == 0 {
+						entry.Tags = []TokenPos{{Pos: token.Pos()}}
+					}
+					entry.Tags[0].Frequency += minTokenFrequency - frequency
+				}
+			}
+		}
+		if len(entry.Tags) == 0 {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		fi, fj := entries[i].Frequency(), entries[j].Frequency()
+		if fi != fj {
+			return fi > fj
+		}
+		return entries[i].Text < entries[j].Text
+	})
+	return entries
+}
+
+// 把语料词频与词典dict合并后写成LoadDictionary格式的词典
+func (trainer *FrequencyTrainer) WriteDictionary(w io.Writer, dict *Dictionary, weights FrequencyWeights) error {
+	return WriteDictionary(w, trainer.Entries(dict, weights))
+}
+
+// 把分词写成LoadDictionary格式的词典，每行为"分词文本 频率 词性"，有多个词性时
+// 在行尾依次追加"频率 词性"
+//
+// 不带词性的部分只能出现在行尾，因此有多个词性时不带词性的频率并入主词性。
+// 注意LoadDictionary会忽略总频率小于2的分词，只用小语料训练时可以调大CorpusWeight。
+func WriteDictionary(w io.Writer, entries []DictionaryEntry) error {
+	buf := bufio.NewWriter(w)
+	for _, entry := range entries {
+		if len(entry.Tags) == 0 || strings.ContainsAny(entry.Text, " \t") {
+			// 带空白的分词无法被LoadDictionary读入
+			continue
+		}
+		tags := foldUntagged(entry.Tags)
+		buf.WriteString(entry.Text)
+		for _, tag := range tags {
+			buf.WriteByte(' ')
+			buf.WriteString(strconv.Itoa(tag.Frequency))
+			if tag.Pos != "" {
+				buf.WriteByte(' ')
+				buf.WriteString(tag.Pos)
+			}
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.Flush()
+}
+
+// 有多个词性时把不带词性的频率并入主词性
+func foldUntagged(tags []TokenPos) []TokenPos {
+	if len(tags) == 1 {
+		return tags
+	}
+	output := make([]TokenPos, 0, len(tags))
+	untagged := 0
+	for _, tag := range tags {
+		if tag.Pos == "" {
+			untagged += tag.Frequency
+		} else {
+			output = append(output, tag)
+		}
+	}
+	if len(output) > 0 {
+		output[0].Frequency += untagged
+	}
+	return output
+}
@@ -0,0 +1,69 @@
+package sego
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFrequencyTrainerKeepsDictionaryWords(t *testing.T) {
+	dir := t.TempDir()
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, dir, "dict.txt",
+		"中国 100 ns",
+		"罕见 2 n",
+	))
+
+	trainer := NewFrequencyTrainer()
+	if err := trainer.Train(strings.NewReader("中国 新词 新词\n人民/n 中国/ns\n")); err != nil {
+		t.Fatal(err)
+	}
+	var output bytes.Buffer
+	if err := trainer.WriteDictionary(&output, seg.Dictionary(), DefaultFrequencyWeights()); err != nil {
+		t.Fatal(err)
+	}
+
+	var merged Segmenter
+	merged.LoadDictionary(writeTestFile(t, dir, "merged.txt", output.String()))
+	dict := merged.Dictionary()
+
+	// 罕见在语料中没有出现，合并后的频率补足到minTokenFrequency
+	token := dict.Lookup("罕见")
+	if token == nil {
+		t.Fatalf("Lookup(罕见) = nil after merge, dictionary:\n%s", output.String())
+	}
+	if token.Frequency() != minTokenFrequency || token.Pos() != "n" {
+		t.Errorf("Lookup(罕见) = %d %q, want %d \"n\"", token.Frequency(), token.Pos(), minTokenFrequency)
+	}
+
+	// 不带词性、词典中也没有的分词默认不带词性
+	if token := dict.Lookup("新词"); token == nil || !reflect.DeepEqual(token.PosTags(), []TokenPos{{"", token.Frequency()}}) {
+		t.Errorf("Lookup(新词) = %v, want untagged token, dictionary:\n%s", token, output.String())
+	}
+	// 不带词性的分词使用词典中的主词性
+	if token := dict.Lookup("中国"); token == nil || !reflect.DeepEqual(token.PosTags(), []TokenPos{{"ns", token.Frequency()}}) {
+		t.Errorf("Lookup(中国) = %v, want ns only", token)
+	}
+	if token := dict.Lookup("人民"); token == nil || token.Pos() != "n" {
+		t.Errorf("Lookup(人民) = %v, want n", token)
+	}
+}
+
+func TestFrequencyTrainerDictionaryWeightZero(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt", "罕见 2 n"))
+	trainer := NewFrequencyTrainer()
+	if err := trainer.Train(strings.NewReader("中国 中国\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// DictionaryWeight为0时只使用语料词频
+	weights := DefaultFrequencyWeights()
+	weights.DictionaryWeight = 0
+	for _, entry := range trainer.Entries(seg.Dictionary(), weights) {
+		if entry.Text == "罕见" {
+			t.Errorf("Entries() contains %v with DictionaryWeight 0", entry)
+		}
+	}
+}
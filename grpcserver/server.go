@@ -0,0 +1,112 @@
+// sego分词的gRPC服务实现，服务定义见proto/sego.proto
+//
+// 用法：
+//	s := grpc.NewServer()
+//	segopb.RegisterSegmenterServer(s, grpcserver.New(&segmenter))
+//	s.Serve(listener)
+// 测试时可以用google.golang.org/grpc/test/bufconn的内存listener代替网络listener。
+package grpcserver
+
+import (
+	"context"
+	"io"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/segopb"
+)
+
+// 包装Segmenter的gRPC服务，Segmenter载入词典后可以被多个请求并发使用
+type Server struct {
+	segopb.UnimplementedSegmenterServer
+
+	segmenter *sego.Segmenter
+}
+
+// 新建使用segmenter分词的服务，segmenter需已载入词典
+func New(segmenter *sego.Segmenter) *Server {
+	return &Server{segmenter: segmenter}
+}
+
+// 对一段文本分词
+func (s *Server) Segment(ctx context.Context, request *segopb.SegmentRequest) (*segopb.SegmentResponse, error) {
+	return s.segment(request), nil
+}
+
+// 对多段文本流式分词，客户端关闭发送端后结束
+func (s *Server) SegmentStream(stream segopb.Segmenter_SegmentStreamServer) error {
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(s.segment(request)); err != nil {
+			return err
+		}
+	}
+}
+
+// 全切分，输出所有可能的分词
+func (s *Server) SegmentAll(ctx context.Context, request *segopb.SegmentRequest) (*segopb.SegmentAllResponse, error) {
+	cuts := s.segmenter.SegmentAll([]byte(request.GetText()))
+	response := &segopb.SegmentAllResponse{Segments: make([]*segopb.Segment, len(cuts))}
+	for i, cut := range cuts {
+		response.Segments[i] = &segopb.Segment{
+			Text:  cut.Token,
+			Pos:   cut.Pos,
+			Start: int32(cut.Start),
+			End:   int32(cut.End),
+		}
+	}
+	return response, nil
+}
+
+// 在词典中查找分词，分词不在词典中时Found为false
+func (s *Server) Lookup(ctx context.Context, request *segopb.LookupRequest) (*segopb.LookupResponse, error) {
+	if request.GetText() == "" {
+		return nil, status.Error(codes.InvalidArgument, "分词文本为空")
+	}
+	token := s.segmenter.Dictionary().Lookup(request.GetText())
+	if token == nil {
+		return &segopb.LookupResponse{}, nil
+	}
+	response := &segopb.LookupResponse{
+		Found:     true,
+		Text:      token.Text(),
+		Frequency: int64(token.Frequency()),
+		Pos:       token.Pos(),
+		Pinyin:    token.Pinyin(),
+	}
+	for _, tag := range token.PosTags() {
+		response.Tags = append(response.Tags, &segopb.PosTag{Pos: tag.Pos, Frequency: int64(tag.Frequency)})
+	}
+	return response, nil
+}
+
+func (s *Server) segment(request *segopb.SegmentRequest) *segopb.SegmentResponse {
+	segs := s.segmenter.Segment([]byte(request.GetText()))
+	response := &segopb.SegmentResponse{Segments: make([]*segopb.Segment, len(segs))}
+	for i := range segs {
+		response.Segments[i] = &segopb.Segment{
+			Text:  segs[i].Token().Text(),
+			Pos:   segs[i].Pos(),
+			Start: int32(segs[i].Start()),
+			End:   int32(segs[i].End()),
+		}
+	}
+	if request.GetSearchMode() {
+		for _, term := range sego.SearchTerms(segs) {
+			response.SearchTerms = append(response.SearchTerms, &segopb.SearchTerm{
+				Text:  term.Text,
+				Start: int32(term.Start),
+				End:   int32(term.End),
+			})
+		}
+	}
+	return response
+}
@@ -0,0 +1,201 @@
+package grpcserver
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/segopb"
+)
+
+const testDictionary = `中华 50 nz
+人民 100 n
+共和国 60 n
+中华人民共和国 20 ns
+发展 80 v 20 vn
+`
+
+// 启动使用内存listener的服务，返回连接到该服务的客户端
+func newTestClient(t *testing.T) segopb.SegmenterClient {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(file, []byte(testDictionary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	segopb.RegisterSegmenterServer(server, New(segmenter))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return segopb.NewSegmenterClient(conn)
+}
+
+func segmentFields(segments []*segopb.Segment) [][4]interface{} {
+	output := make([][4]interface{}, len(segments))
+	for i, s := range segments {
+		output[i] = [4]interface{}{s.GetText(), s.GetPos(), s.GetStart(), s.GetEnd()}
+	}
+	return output
+}
+
+func termFields(terms []*segopb.SearchTerm) [][3]interface{} {
+	output := make([][3]interface{}, len(terms))
+	for i, term := range terms {
+		output[i] = [3]interface{}{term.GetText(), term.GetStart(), term.GetEnd()}
+	}
+	return output
+}
+
+func TestSegment(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	wantSegments := [][4]interface{}{
+		{"中华人民共和国", "ns", int32(0), int32(7)},
+		{"发展", "v", int32(7), int32(9)},
+	}
+
+	response, err := client.Segment(ctx, &segopb.SegmentRequest{Text: "中华人民共和国发展"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := segmentFields(response.GetSegments()); !reflect.DeepEqual(got, wantSegments) {
+		t.Errorf("Segment() segments = %v, want %v", got, wantSegments)
+	}
+	if len(response.GetSearchTerms()) != 0 {
+		t.Errorf("Segment() without search_mode returned search terms %v", termFields(response.GetSearchTerms()))
+	}
+
+	response, err = client.Segment(ctx, &segopb.SegmentRequest{Text: "中华人民共和国发展", SearchMode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := segmentFields(response.GetSegments()); !reflect.DeepEqual(got, wantSegments) {
+		t.Errorf("Segment() segments = %v, want %v", got, wantSegments)
+	}
+	wantTerms := [][3]interface{}{
+		{"中华", int32(0), int32(2)},
+		{"人民", int32(2), int32(4)},
+		{"共和国", int32(4), int32(7)},
+		{"中华人民共和国", int32(0), int32(7)},
+		{"发展", int32(7), int32(9)},
+	}
+	if got := termFields(response.GetSearchTerms()); !reflect.DeepEqual(got, wantTerms) {
+		t.Errorf("Segment() search terms = %v, want %v", got, wantTerms)
+	}
+}
+
+func TestSegmentStream(t *testing.T) {
+	client := newTestClient(t)
+	stream, err := client.SegmentStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	texts := []string{"人民", "发展", ""}
+	for _, text := range texts {
+		if err := stream.Send(&segopb.SegmentRequest{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][][4]interface{}{
+		{{"人民", "n", int32(0), int32(2)}},
+		{{"发展", "v", int32(0), int32(2)}},
+		{},
+	}
+	for i := 0; ; i++ {
+		response, err := stream.Recv()
+		if err == io.EOF {
+			if i != len(want) {
+				t.Errorf("SegmentStream() returned %d responses, want %d", i, len(want))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i >= len(want) {
+			t.Fatalf("SegmentStream() returned more than %d responses", len(want))
+		}
+		if got := segmentFields(response.GetSegments()); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("SegmentStream() response %d = %v, want %v", i, got, want[i])
+		}
+	}
+}
+
+func TestSegmentAll(t *testing.T) {
+	client := newTestClient(t)
+	response, err := client.SegmentAll(context.Background(), &segopb.SegmentRequest{Text: "人民共和国"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][4]interface{}{
+		{"人民", "n", int32(0), int32(2)},
+		{"共和国", "n", int32(2), int32(5)},
+	}
+	if got := segmentFields(response.GetSegments()); !reflect.DeepEqual(got, want) {
+		t.Errorf("SegmentAll() = %v, want %v", got, want)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	response, err := client.Lookup(ctx, &segopb.LookupRequest{Text: "发展"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.GetFound() || response.GetText() != "发展" || response.GetFrequency() != 100 || response.GetPos() != "v" {
+		t.Errorf("Lookup(发展) = %v", response)
+	}
+	var tags []string
+	for _, tag := range response.GetTags() {
+		tags = append(tags, tag.GetPos())
+	}
+	if !reflect.DeepEqual(tags, []string{"v", "vn"}) || response.GetTags()[1].GetFrequency() != 20 {
+		t.Errorf("Lookup(发展) tags = %v", response.GetTags())
+	}
+
+	response, err = client.Lookup(ctx, &segopb.LookupRequest{Text: "经济"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.GetFound() || response.GetText() != "" {
+		t.Errorf("Lookup(经济) = %v, want not found", response)
+	}
+
+	_, err = client.Lookup(ctx, &segopb.LookupRequest{Text: ""})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Lookup(\"\") error = %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,240 @@
+// 搜索结果的关键词高亮和摘要生成
+package highlight
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/crossgit/sego"
+)
+
+// 高亮格式
+type Format struct {
+	// 高亮部分前后插入的标记
+	Pre  string
+	Post string
+
+	// 对原文的转义，为nil时不转义
+	Escape func(string) string
+}
+
+var (
+	// HTML格式，用<em>标记高亮部分并转义原文
+	HTML = Format{Pre: "<em>", Post: "</em>", Escape: html.EscapeString}
+
+	// 终端格式，用红色粗体显示高亮部分
+	ANSI = Format{Pre: "\x1b[1;31m", Post: "\x1b[0m"}
+)
+
+const (
+	defaultFragmentLength = 100
+	defaultEllipsis       = "…"
+)
+
+// 文档中匹配查询词的一段文本
+type Match struct {
+	// 匹配部分在文档中的起止字节位置（不包括End）
+	Start int
+	End   int
+
+	// 匹配的查询词
+	Term string
+}
+
+// 高亮器，查询和文档用同一个分词器切分，文档按搜索模式切分以便匹配子分词
+type Highlighter struct {
+	segmenter      *sego.Segmenter
+	format         Format
+	fragmentLength int
+	ellipsis       string
+}
+
+// 新建使用segmenter分词、按format输出的高亮器
+func New(segmenter *sego.Segmenter, format Format) *Highlighter {
+	return &Highlighter{
+		segmenter:      segmenter,
+		format:         format,
+		fragmentLength: defaultFragmentLength,
+		ellipsis:       defaultEllipsis,
+	}
+}
+
+// 设置摘要的目标长度（字数），默认为100
+func (h *Highlighter) SetFragmentLength(length int) {
+	if length > 0 {
+		h.fragmentLength = length
+	}
+}
+
+// 设置摘要被截断时首尾添加的省略号，默认为"…"
+func (h *Highlighter) SetEllipsis(ellipsis string) {
+	h.ellipsis = ellipsis
+}
+
+// 返回文档中所有匹配查询词的位置，相交的匹配会被合并，结果按起始位置排列
+func (h *Highlighter) Matches(query, document []byte) []Match {
+	return toByteMatches(h.runeMatches(query, document), runeToByteOffsets(document))
+}
+
+// 高亮整个文档
+func (h *Highlighter) Highlight(query, document []byte) string {
+	offsets := runeToByteOffsets(document)
+	return h.render(document, toByteMatches(h.runeMatches(query, document), offsets), 0, len(document))
+}
+
+// 生成一段长度约为SetFragmentLength的摘要，摘要取自匹配查询词最多的区域
+//
+// 区域的得分为其中不同查询词的个数乘以10再加上匹配的总次数，得分相同时取靠前的区域。
+// 文档中没有匹配时返回文档的开头。
+func (h *Highlighter) Snippet(query, document []byte) string {
+	runeMatches := h.runeMatches(query, document)
+	offsets := runeToByteOffsets(document)
+	numRunes := len(offsets) - 1
+
+	// 以每个匹配为中心选取候选区域
+	bestStart, bestScore := 0, -1
+	for _, m := range runeMatches {
+		start := m.Start - (h.fragmentLength-(m.End-m.Start))/2
+		start = maxInt(0, minInt(start, numRunes-h.fragmentLength))
+		if score := windowScore(runeMatches, start, start+h.fragmentLength); score > bestScore {
+			bestStart, bestScore = start, score
+		}
+	}
+	bestEnd := minInt(numRunes, bestStart+h.fragmentLength)
+
+	// 只保留完全落在区域内的匹配
+	inside := make([]Match, 0)
+	for _, m := range runeMatches {
+		if m.Start >= bestStart && m.End <= bestEnd {
+			inside = append(inside, m)
+		}
+	}
+
+	output := h.render(document, toByteMatches(inside, offsets), offsets[bestStart], offsets[bestEnd])
+	if bestStart > 0 {
+		output = h.ellipsis + output
+	}
+	if bestEnd < numRunes {
+		output += h.ellipsis
+	}
+	return output
+}
+
+// 按字计算文档中匹配查询词的位置
+func (h *Highlighter) runeMatches(query, document []byte) []Match {
+	terms := make(map[string]bool)
+	for _, term := range sego.SearchTerms(h.segmenter.Segment(query)) {
+		if isIndexable(term.Text) {
+			terms[term.Text] = true
+		}
+	}
+
+	matches := make([]Match, 0)
+	for _, term := range sego.SearchTerms(h.segmenter.Segment(document)) {
+		if terms[term.Text] {
+			matches = append(matches, Match{Start: term.Start, End: term.End, Term: term.Text})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Start != matches[j].Start {
+			return matches[i].Start < matches[j].Start
+		}
+		return matches[i].End > matches[j].End
+	})
+
+	// 合并相交的匹配，保留较长的查询词
+	merged := make([]Match, 0, len(matches))
+	for _, m := range matches {
+		if n := len(merged); n > 0 && m.Start < merged[n-1].End {
+			if m.End > merged[n-1].End {
+				merged[n-1].End = m.End
+			}
+			continue
+		}
+		merged = append(merged, m)
+	}
+	return merged
+}
+
+// 计算区域[start, end)的得分
+func windowScore(matches []Match, start, end int) int {
+	distinct := make(map[string]bool)
+	total := 0
+	for _, m := range matches {
+		if m.Start >= start && m.End <= end {
+			distinct[m.Term] = true
+			total++
+		}
+	}
+	return len(distinct)*10 + total
+}
+
+// 输出document[start:end]，并高亮其中的匹配
+func (h *Highlighter) render(document []byte, matches []Match, start, end int) string {
+	var output strings.Builder
+	escape := h.format.Escape
+	if escape == nil {
+		escape = func(s string) string { return s }
+	}
+	current := start
+	for _, m := range matches {
+		if m.Start < start || m.End > end {
+			continue
+		}
+		output.WriteString(escape(string(document[current:m.Start])))
+		output.WriteString(h.format.Pre)
+		output.WriteString(escape(string(document[m.Start:m.End])))
+		output.WriteString(h.format.Post)
+		current = m.End
+	}
+	output.WriteString(escape(string(document[current:end])))
+	return output.String()
+}
+
+// 分词的位置以字计算，返回每个字在文档中的字节位置，最后一项为文档的字节长度
+func runeToByteOffsets(document []byte) []int {
+	offsets := make([]int, 0, len(document)+1)
+	for i := range string(document) {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(document))
+}
+
+func toByteMatches(matches []Match, offsets []int) []Match {
+	output := make([]Match, 0, len(matches))
+	last := len(offsets) - 1
+	for _, m := range matches {
+		output = append(output, Match{
+			Start: offsets[minInt(m.Start, last)],
+			End:   offsets[minInt(m.End, last)],
+			Term:  m.Term,
+		})
+	}
+	return output
+}
+
+// 只有包含文字或数字的词条才参与匹配，标点和空白不高亮
+func isIndexable(term string) bool {
+	for _, r := range term {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func minInt(a, b int) int {
+	if a > b {
+		return b
+	}
+	return a
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
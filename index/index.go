@@ -0,0 +1,258 @@
+// 基于sego分词的内存倒排索引，适合小规模的中文全文检索
+//
+// 文档按搜索模式切分，索引分词及其子分词的所有词条。词条的位置为所属分词在文档中
+// 的序号，标点和空白不占用位置，子分词与分词本身位置相同。查询支持词条、短语和布尔
+// 组合，结果按BM25排序。
+// 索引可以用Save保存到磁盘，用Load载入。
+package index
+
+import (
+	"encoding/gob"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"sync"
+	"unicode"
+
+	"github.com/crossgit/sego"
+)
+
+const (
+	defaultK1 = 1.2
+	defaultB  = 0.75
+)
+
+// 一条检索结果
+type Hit struct {
+	// 文档标识
+	ID string
+
+	// BM25得分
+	Score float64
+}
+
+// 倒排索引，可以被多个goroutine并发使用
+type Index struct {
+	segmenter *sego.Segmenter
+	lock      sync.RWMutex
+	data      indexData
+	k1, b     float64
+}
+
+// 索引的内容，Save和Load时整体读写
+type indexData struct {
+	// 内部文档编号到文档的映射
+	Docs map[int]*document
+
+	// 文档标识到内部编号的映射
+	DocIDs map[string]int
+
+	// 下一个文档的内部编号
+	NextDoc int
+
+	// 词条 -> 内部文档编号 -> 词条在文档中的位置（升序，可能重复）
+	Postings map[string]map[int][]int
+
+	// 所有文档的词条总数，用于计算平均文档长度
+	TotalLength int
+}
+
+type document struct {
+	ID string
+
+	// 文档的词条数
+	Length int
+
+	// 文档中出现的不同词条，删除文档时用于清理倒排表
+	Terms []string
+}
+
+// 新建使用segmenter分词的空索引
+func New(segmenter *sego.Segmenter) *Index {
+	return &Index{
+		segmenter: segmenter,
+		data: indexData{
+			Docs:     make(map[int]*document),
+			DocIDs:   make(map[string]int),
+			Postings: make(map[string]map[int][]int),
+		},
+		k1: defaultK1,
+		b:  defaultB,
+	}
+}
+
+// 设置BM25的参数，默认k1为1.2，b为0.75
+func (ix *Index) SetBM25(k1, b float64) {
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+	ix.k1, ix.b = k1, b
+}
+
+// 返回索引中的文档数
+func (ix *Index) Len() int {
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+	return len(ix.data.Docs)
+}
+
+// 添加文档，已有同一标识的文档时替换原文档
+func (ix *Index) Add(id string, text []byte) {
+	segs := ix.segmenter.Segment(text)
+
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+
+	ix.delete(id)
+	docNum := ix.data.NextDoc
+	ix.data.NextDoc++
+	doc := &document{ID: id}
+	position := 0
+	for i := range segs {
+		indexed := false
+		for _, term := range sego.SearchTerms(segs[i : i+1]) {
+			if !isIndexable(term.Text) {
+				continue
+			}
+			postings, ok := ix.data.Postings[term.Text]
+			if !ok {
+				postings = make(map[int][]int)
+				ix.data.Postings[term.Text] = postings
+			}
+			if _, ok := postings[docNum]; !ok {
+				doc.Terms = append(doc.Terms, term.Text)
+			}
+			postings[docNum] = append(postings[docNum], position)
+			doc.Length++
+			indexed = true
+		}
+		if indexed {
+			position++
+		}
+	}
+
+	ix.data.Docs[docNum] = doc
+	ix.data.DocIDs[id] = docNum
+	ix.data.TotalLength += doc.Length
+}
+
+// 删除文档，文档不存在时返回false
+func (ix *Index) Delete(id string) bool {
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+	return ix.delete(id)
+}
+
+func (ix *Index) delete(id string) bool {
+	docNum, ok := ix.data.DocIDs[id]
+	if !ok {
+		return false
+	}
+	doc := ix.data.Docs[docNum]
+	for _, term := range doc.Terms {
+		postings := ix.data.Postings[term]
+		delete(postings, docNum)
+		if len(postings) == 0 {
+			delete(ix.data.Postings, term)
+		}
+	}
+	ix.data.TotalLength -= doc.Length
+	delete(ix.data.Docs, docNum)
+	delete(ix.data.DocIDs, id)
+	return true
+}
+
+// 检索满足query的文档，按得分从高到低返回前limit个，limit不大于0时返回全部
+func (ix *Index) Search(query Query, limit int) []Hit {
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+
+	scores := query.search(ix)
+	hits := make([]Hit, 0, len(scores))
+	for docNum, score := range scores {
+		hits = append(hits, Hit{ID: ix.data.Docs[docNum].ID, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// 计算词条在文档中出现freq次时的BM25得分，df为包含词条的文档数
+func (ix *Index) bm25(docNum, freq, df int) float64 {
+	n := float64(len(ix.data.Docs))
+	idf := math.Log(1 + (n-float64(df)+0.5)/(float64(df)+0.5))
+	avgLength := float64(ix.data.TotalLength) / n
+	length := float64(ix.data.Docs[docNum].Length)
+	tf := float64(freq)
+	return idf * tf * (ix.k1 + 1) / (tf + ix.k1*(1-ix.b+ix.b*length/avgLength))
+}
+
+// 把索引写入w
+func (ix *Index) Save(w io.Writer) error {
+	ix.lock.RLock()
+	defer ix.lock.RUnlock()
+	return gob.NewEncoder(w).Encode(&ix.data)
+}
+
+// 从r中载入Save写出的索引，替换索引的全部内容
+func (ix *Index) Load(r io.Reader) error {
+	var data indexData
+	if err := gob.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	// gob不区分空map和nil
+	if data.Docs == nil {
+		data.Docs = make(map[int]*document)
+	}
+	if data.DocIDs == nil {
+		data.DocIDs = make(map[string]int)
+	}
+	if data.Postings == nil {
+		data.Postings = make(map[string]map[int][]int)
+	}
+
+	ix.lock.Lock()
+	defer ix.lock.Unlock()
+	ix.data = data
+	return nil
+}
+
+// 把索引保存到文件
+func (ix *Index) SaveFile(file string) error {
+	output, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	if err := ix.Save(output); err != nil {
+		output.Close()
+		return err
+	}
+	return output.Close()
+}
+
+// 从文件载入索引
+func (ix *Index) LoadFile(file string) error {
+	input, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	return ix.Load(input)
+}
+
+// 只有包含文字或数字的词条才被索引，标点和空白不索引
+func isIndexable(term string) bool {
+	for _, r := range term {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return true
+		}
+	}
+	return false
+}
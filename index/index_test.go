@@ -0,0 +1,186 @@
+package index
+
+import (
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/crossgit/sego"
+)
+
+const testDictionary = `中华 50 nz
+人民 100 n
+共和国 60 n
+中华人民共和国 200 ns
+发展 100 v
+经济 100 n
+`
+
+// 新建包含三个文档的索引
+//	a: 人民/0 发展/1 经济/2
+//	b: 经济/0 发展/1 人民/2
+//	c: 中华人民共和国/0（子分词中华、人民、共和国也在0） 发展/1
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(file, []byte(testDictionary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+	ix := New(segmenter)
+	ix.Add("a", []byte("人民 发展 经济"))
+	ix.Add("b", []byte("经济发展，人民"))
+	ix.Add("c", []byte("中华人民共和国 发展"))
+	return ix
+}
+
+func hitIDs(hits []Hit) []string {
+	output := make([]string, len(hits))
+	for i, hit := range hits {
+		output[i] = hit.ID
+	}
+	return output
+}
+
+func TestBM25(t *testing.T) {
+	ix := newTestIndex(t)
+
+	// 文档长度为3、3、5，"共和国"只出现在c中
+	idf := math.Log(1 + (3-1+0.5)/(1+0.5))
+	avgLength := 11.0 / 3
+	want := idf * 1 * (1.2 + 1) / (1 + 1.2*(1-0.75+0.75*5/avgLength))
+	hits := ix.Search(Term("共和国"), 0)
+	if len(hits) != 1 || hits[0].ID != "c" || math.Abs(hits[0].Score-want) > 1e-9 {
+		t.Errorf("Search(共和国) = %v, want [c %v]", hits, want)
+	}
+
+	// 长度相同、词频相同的文档得分相同，按标识排序
+	hits = ix.Search(Term("经济"), 0)
+	if !reflect.DeepEqual(hitIDs(hits), []string{"a", "b"}) || hits[0].Score != hits[1].Score {
+		t.Errorf("Search(经济) = %v, want a and b with equal scores", hits)
+	}
+
+	// b为0时不考虑文档长度
+	ix.SetBM25(1.2, 0)
+	want = idf * 1 * (1.2 + 1) / (1 + 1.2)
+	if hits := ix.Search(Term("共和国"), 0); len(hits) != 1 || math.Abs(hits[0].Score-want) > 1e-9 {
+		t.Errorf("Search(共和国) with b=0 = %v, want %v", hits, want)
+	}
+}
+
+func TestQueries(t *testing.T) {
+	ix := newTestIndex(t)
+	cases := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"term", Term("人民"), []string{"a", "b", "c"}},
+		{"term is not segmented", Term("人民发展"), []string{}},
+		// c只包含其中一个分词，得分最低
+		{"match", Match("人民经济"), []string{"a", "b", "c"}},
+		{"match none", Match("农业"), []string{}},
+
+		{"phrase", Phrase("发展经济"), []string{"a"}},
+		{"phrase reversed", Phrase("经济发展"), []string{"b"}},
+		// 查询中的空白和文档中的标点不占用位置
+		{"phrase whitespace", Phrase("发展 \t 经济"), []string{"a"}},
+		{"phrase across punctuation", Phrase("发展人民"), []string{"b"}},
+		// 子分词与分词本身位置相同
+		{"phrase sub-term", Phrase("人民发展"), []string{"a", "c"}},
+		{"phrase missing term", Phrase("人民农业"), []string{}},
+
+		{"and", And(Term("人民"), Term("经济")), []string{"a", "b"}},
+		{"and not", And(Term("人民"), Not(Term("经济"))), []string{"c"}},
+		{"not", Not(Term("经济")), []string{"c"}},
+		// "共和国"只出现在c中，IDF最高
+		{"or", Or(Term("共和国"), Phrase("发展经济")), []string{"c", "a"}},
+		{"empty and", And(), []string{}},
+
+		{"parse and", Parse("人民 经济"), []string{"a", "b"}},
+		{"parse or", Parse("共和国 OR 经济"), []string{"c", "a", "b"}},
+		{"parse not", Parse("人民 -经济"), []string{"c"}},
+		{"parse phrase", Parse(`"发展 经济"`), []string{"a"}},
+		{"parse phrase and match", Parse(`经济 "人民 发展"`), []string{"a"}},
+		{"parse negated phrase", Parse(`发展 -"发展经济"`), []string{"b", "c"}},
+	}
+	for _, c := range cases {
+		if got := hitIDs(ix.Search(c.query, 0)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Search() = %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	if got := hitIDs(ix.Search(Term("人民"), 2)); len(got) != 2 {
+		t.Errorf("Search(limit 2) = %q, want 2 hits", got)
+	}
+}
+
+func TestAddDelete(t *testing.T) {
+	ix := newTestIndex(t)
+
+	// 同一标识的文档被替换
+	ix.Add("a", []byte("共和国"))
+	if got := hitIDs(ix.Search(Term("共和国"), 0)); !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Search(共和国) = %q, want [a c]", got)
+	}
+	if got := hitIDs(ix.Search(Term("经济"), 0)); !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("Search(经济) = %q, want [b]", got)
+	}
+
+	if !ix.Delete("b") || ix.Delete("b") {
+		t.Error("Delete(b) should succeed once")
+	}
+	if ix.Len() != 2 || len(ix.Search(Term("经济"), 0)) != 0 {
+		t.Errorf("after Delete(b): Len() = %d, Search(经济) = %v", ix.Len(), ix.Search(Term("经济"), 0))
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	ix := newTestIndex(t)
+	queries := []Query{Term("人民"), Match("人民经济"), Phrase("发展经济"), Parse("人民 -经济")}
+
+	var buffer bytes.Buffer
+	if err := ix.Save(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	loaded := New(ix.segmenter)
+	if err := loaded.Load(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "index.gob")
+	if err := ix.SaveFile(file); err != nil {
+		t.Fatal(err)
+	}
+	loadedFile := New(ix.segmenter)
+	if err := loadedFile.LoadFile(file); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, other := range []*Index{loaded, loadedFile} {
+		if other.Len() != ix.Len() {
+			t.Errorf("Len() = %d after Load, want %d", other.Len(), ix.Len())
+		}
+		for _, query := range queries {
+			if got, want := other.Search(query, 0), ix.Search(query, 0); !reflect.DeepEqual(got, want) {
+				t.Errorf("Search(%v) after Load = %v, want %v", query, got, want)
+			}
+		}
+	}
+
+	// 载入的索引可以继续添加和删除文档
+	loaded.Add("d", []byte("经济"))
+	loaded.Delete("a")
+	if got := hitIDs(loaded.Search(Term("经济"), 0)); !reflect.DeepEqual(got, []string{"d", "b"}) {
+		t.Errorf("Search(经济) after Load and Add = %q, want [d b]", got)
+	}
+
+	if err := New(ix.segmenter).LoadFile(file + ".missing"); err == nil {
+		t.Error("LoadFile() with missing file returned nil error")
+	}
+}
@@ -0,0 +1,279 @@
+package index
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// 检索条件，由Term、Match、Phrase、And、Or、Not或Parse构造
+type Query interface {
+	// 返回满足条件的文档的内部编号及其得分，调用时已持有索引的读锁
+	search(ix *Index) map[int]float64
+}
+
+type termQuery struct {
+	term string
+}
+
+// 返回包含词条term的文档，term不再分词
+func Term(term string) Query {
+	return termQuery{term: strings.ToLower(term)}
+}
+
+func (q termQuery) search(ix *Index) map[int]float64 {
+	postings := ix.data.Postings[q.term]
+	output := make(map[int]float64, len(postings))
+	for docNum, positions := range postings {
+		output[docNum] = ix.bm25(docNum, len(positions), len(postings))
+	}
+	return output
+}
+
+type matchQuery struct {
+	text string
+}
+
+// 对text分词，返回包含其中任意一个分词的文档，包含的分词越多得分越高
+func Match(text string) Query {
+	return matchQuery{text: text}
+}
+
+func (q matchQuery) search(ix *Index) map[int]float64 {
+	queries := make([]Query, 0)
+	for _, term := range queryTerms(ix, q.text) {
+		queries = append(queries, termQuery{term: term})
+	}
+	return orQuery{queries: queries}.search(ix)
+}
+
+type phraseQuery struct {
+	text string
+}
+
+// 对text分词，返回按相同顺序连续包含所有分词的文档
+//
+// 文档中的标点和空白不占用位置，因此分词之间的空白和标点不影响匹配。文档中分词的
+// 子分词与分词本身位置相同，比如"人民"可以匹配"中华人民共和国"中的子分词。
+func Phrase(text string) Query {
+	return phraseQuery{text: text}
+}
+
+func (q phraseQuery) search(ix *Index) map[int]float64 {
+	output := make(map[int]float64)
+	terms := queryTerms(ix, q.text)
+	if len(terms) == 0 {
+		return output
+	}
+	postings := make([]map[int][]int, len(terms))
+	for i, term := range terms {
+		postings[i] = ix.data.Postings[term]
+		if len(postings[i]) == 0 {
+			return output
+		}
+	}
+
+	for docNum, firstPositions := range postings[0] {
+		freq := 0
+		for _, start := range firstPositions {
+			if phraseAt(postings, docNum, start) {
+				freq++
+			}
+		}
+		if freq == 0 {
+			continue
+		}
+		for i := range terms {
+			output[docNum] += ix.bm25(docNum, freq, len(postings[i]))
+		}
+	}
+	return output
+}
+
+// 判断文档中从位置start开始是否依次出现短语的各个词条
+func phraseAt(postings []map[int][]int, docNum, start int) bool {
+	for i := 1; i < len(postings); i++ {
+		positions := postings[i][docNum]
+		position := start + i
+		j := sort.SearchInts(positions, position)
+		if j == len(positions) || positions[j] != position {
+			return false
+		}
+	}
+	return true
+}
+
+// 按精确模式对查询分词，返回可索引的分词
+func queryTerms(ix *Index, text string) []string {
+	output := make([]string, 0)
+	segs := ix.segmenter.Segment([]byte(text))
+	for i := range segs {
+		if word := segs[i].Token().Text(); isIndexable(word) {
+			output = append(output, word)
+		}
+	}
+	return output
+}
+
+type andQuery struct {
+	queries []Query
+}
+
+// 返回满足所有条件的文档，得分为各条件得分之和。参数中的Not条件表示排除满足该条件的文档
+func And(queries ...Query) Query {
+	return andQuery{queries: queries}
+}
+
+func (q andQuery) search(ix *Index) map[int]float64 {
+	if len(q.queries) == 0 {
+		return make(map[int]float64)
+	}
+	var output map[int]float64
+	excluded := make([]map[int]float64, 0)
+	for _, query := range q.queries {
+		if not, ok := query.(notQuery); ok {
+			excluded = append(excluded, not.query.search(ix))
+			continue
+		}
+		scores := query.search(ix)
+		if output == nil {
+			output = scores
+			continue
+		}
+		for docNum, score := range output {
+			if s, ok := scores[docNum]; ok {
+				output[docNum] = score + s
+			} else {
+				delete(output, docNum)
+			}
+		}
+	}
+	if output == nil {
+		// 只有Not条件时从所有文档中排除
+		output = allDocs(ix)
+	}
+	for _, scores := range excluded {
+		for docNum := range scores {
+			delete(output, docNum)
+		}
+	}
+	return output
+}
+
+type orQuery struct {
+	queries []Query
+}
+
+// 返回满足任意一个条件的文档，得分为满足的条件得分之和
+func Or(queries ...Query) Query {
+	return orQuery{queries: queries}
+}
+
+func (q orQuery) search(ix *Index) map[int]float64 {
+	output := make(map[int]float64)
+	for _, query := range q.queries {
+		for docNum, score := range query.search(ix) {
+			output[docNum] += score
+		}
+	}
+	return output
+}
+
+type notQuery struct {
+	query Query
+}
+
+// 返回不满足条件的文档，得分为0。通常作为And的参数使用
+func Not(query Query) Query {
+	return notQuery{query: query}
+}
+
+func (q notQuery) search(ix *Index) map[int]float64 {
+	return andQuery{queries: []Query{q}}.search(ix)
+}
+
+func allDocs(ix *Index) map[int]float64 {
+	output := make(map[int]float64, len(ix.data.Docs))
+	for docNum := range ix.data.Docs {
+		output[docNum] = 0
+	}
+	return output
+}
+
+// 解析查询字符串，语法与常见搜索引擎相同：
+//	人民 共和国		空白分隔的部分都要满足
+//	人民 OR 群众		OR连接的部分满足一个即可，OR的优先级高于空白
+//	"人民共和国"		短语
+//	-共和国			排除
+// 不带引号的部分按Match处理，比如"中国人民"等价于Match("中国人民")。
+func Parse(query string) Query {
+	var clauses []Query
+	var alternatives []Query
+	pendingOr := false
+
+	flush := func() {
+		if len(alternatives) == 1 {
+			clauses = append(clauses, alternatives[0])
+		} else if len(alternatives) > 1 {
+			clauses = append(clauses, Or(alternatives...))
+		}
+		alternatives = nil
+	}
+
+	for _, part := range splitQuery(query) {
+		if part == "OR" {
+			pendingOr = len(alternatives) > 0
+			continue
+		}
+		negate := strings.HasPrefix(part, "-") && len(part) > 1
+		if negate {
+			part = part[1:]
+		}
+
+		var clause Query
+		if strings.HasPrefix(part, "\"") {
+			clause = Phrase(strings.Trim(part, "\""))
+		} else {
+			clause = Match(part)
+		}
+
+		if negate {
+			flush()
+			clauses = append(clauses, Not(clause))
+			pendingOr = false
+			continue
+		}
+		if !pendingOr {
+			flush()
+		}
+		alternatives = append(alternatives, clause)
+		pendingOr = false
+	}
+	flush()
+	return And(clauses...)
+}
+
+// 按空白切分查询字符串，引号内的空白不切分，引号保留在结果中
+func splitQuery(query string) []string {
+	output := make([]string, 0)
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				output = append(output, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		output = append(output, current.String())
+	}
+	return output
+}
@@ -0,0 +1,93 @@
+// 基于sego分词结果的关键词提取
+package keywords
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/crossgit/sego"
+)
+
+// 候选关键词过滤器，TFIDF和TextRank共用
+//
+// 除了自身的停用词外，分词器中载入的停用词（见Segmenter.LoadStopWords）也会被过滤。
+type filter struct {
+	segmenter  *sego.Segmenter
+	stopWords  map[string]bool
+	allowedPos map[string]bool // 为空时接受所有词性
+	minLength  int             // 关键词的最小字数
+}
+
+func newFilter(segmenter *sego.Segmenter) filter {
+	return filter{
+		segmenter:  segmenter,
+		stopWords:  make(map[string]bool),
+		allowedPos: make(map[string]bool),
+		minLength:  2,
+	}
+}
+
+// 添加停用词
+func (f *filter) AddStopWords(words ...string) {
+	for _, word := range words {
+		f.stopWords[strings.ToLower(word)] = true
+	}
+}
+
+// 从reader中读入停用词，每行一个
+func (f *filter) LoadStopWords(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			f.AddStopWords(word)
+		}
+	}
+	return scanner.Err()
+}
+
+// 从文件中读入停用词，多个文件名用","分隔
+func (f *filter) LoadStopWordsFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		stopFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = f.LoadStopWords(stopFile)
+		stopFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 设置允许作为关键词的词性，不设置时接受所有词性
+func (f *filter) SetAllowedPos(pos ...string) {
+	f.allowedPos = make(map[string]bool)
+	for _, p := range pos {
+		f.allowedPos[p] = true
+	}
+}
+
+// 设置关键词的最小字数，默认为2，即不输出单字
+func (f *filter) SetMinLength(length int) {
+	f.minLength = length
+}
+
+// 判断分词能否作为候选关键词
+func (f *filter) accept(seg *sego.Segment) bool {
+	text := seg.Token().Text()
+	if utf8.RuneCountInString(text) < f.minLength || strings.TrimSpace(text) == "" {
+		return false
+	}
+	if f.stopWords[text] || f.segmenter.IsStopWord(text) {
+		return false
+	}
+	if len(f.allowedPos) > 0 && !f.allowedPos[seg.Pos()] {
+		return false
+	}
+	return true
+}
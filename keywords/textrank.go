@@ -0,0 +1,210 @@
+package keywords
+
+import (
+	"math"
+	"sort"
+
+	"github.com/crossgit/sego"
+)
+
+const (
+	defaultWindow     = 5
+	defaultDamping    = 0.85
+	maxIterations     = 100
+	convergenceDelta  = 1e-4
+	phraseRankDivisor = 3 // 排名在前 1/phraseRankDivisor 的分词参与短语合并
+)
+
+// 由相邻的高排名分词合并而成的关键短语
+type Phrase struct {
+	Text   string  `json:"text"`
+	Weight float64 `json:"weight"`
+
+	// 短语第一次出现的起止位置，与Segment的Start()和End()一致
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// 基于TextRank的关键词和关键短语提取器
+//
+// 在窗口内共同出现的候选分词之间连边，边的权重为共现次数，然后在该图上迭代
+// 计算PageRank值作为分词的权重。与TFIDF不同，TextRank不需要语料的IDF统计，
+// 适合短文本。
+type TextRank struct {
+	filter
+
+	window  int
+	damping float64
+}
+
+// 新建使用segmenter分词的提取器，默认窗口为5，阻尼系数为0.85
+func NewTextRank(segmenter *sego.Segmenter) *TextRank {
+	return &TextRank{
+		filter:  newFilter(segmenter),
+		window:  defaultWindow,
+		damping: defaultDamping,
+	}
+}
+
+// 设置共现窗口的大小，即相距小于window个分词的候选分词之间连边
+func (t *TextRank) SetWindow(window int) {
+	if window > 1 {
+		t.window = window
+	}
+}
+
+// 设置PageRank的阻尼系数
+func (t *TextRank) SetDamping(damping float64) {
+	t.damping = damping
+}
+
+// 对文本分词并返回权重最高的topK个关键词，topK不大于零时返回全部
+func (t *TextRank) Extract(text []byte, topK int) []Keyword {
+	return t.ExtractSegments(t.segmenter.Segment(text), topK)
+}
+
+// 从分词结果中返回权重最高的topK个关键词，topK不大于零时返回全部
+func (t *TextRank) ExtractSegments(segs []sego.Segment, topK int) []Keyword {
+	ranks := t.rank(segs)
+	output := make([]Keyword, 0, len(ranks))
+	for word, weight := range ranks {
+		output = append(output, Keyword{Text: word, Weight: weight})
+	}
+	return topKeywords(output, topK)
+}
+
+// 对文本分词并返回权重最高的topK个关键短语，topK不大于零时返回全部
+func (t *TextRank) ExtractPhrases(text []byte, topK int) []Phrase {
+	return t.ExtractPhrasesFromSegments(t.segmenter.Segment(text), topK)
+}
+
+// 从分词结果中返回权重最高的topK个关键短语
+//
+// 排名靠前的分词在原文中首尾相接（前一分词的End()等于后一分词的Start()）时
+// 合并为短语，短语的权重为其中各分词权重之和。
+func (t *TextRank) ExtractPhrasesFromSegments(segs []sego.Segment, topK int) []Phrase {
+	ranks := t.rank(segs)
+	keywords := make([]Keyword, 0, len(ranks))
+	for word, weight := range ranks {
+		keywords = append(keywords, Keyword{Text: word, Weight: weight})
+	}
+	keywords = topKeywords(keywords, (len(keywords)+phraseRankDivisor-1)/phraseRankDivisor)
+	top := make(map[string]float64, len(keywords))
+	for _, keyword := range keywords {
+		top[keyword.Text] = keyword.Weight
+	}
+
+	phrases := make(map[string]*Phrase)
+	order := make([]string, 0)
+	for i := 0; i < len(segs); {
+		weight, ok := top[segs[i].Token().Text()]
+		if !ok || !t.accept(&segs[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		text := segs[i].Token().Text()
+		for ; j < len(segs) && segs[j].Start() == segs[j-1].End(); j++ {
+			w, ok := top[segs[j].Token().Text()]
+			if !ok || !t.accept(&segs[j]) {
+				break
+			}
+			text += segs[j].Token().Text()
+			weight += w
+		}
+		if j-i > 1 {
+			if phrase, ok := phrases[text]; ok {
+				phrase.Weight = math.Max(phrase.Weight, weight)
+			} else {
+				phrases[text] = &Phrase{
+					Text: text, Weight: weight, Start: segs[i].Start(), End: segs[j-1].End()}
+				order = append(order, text)
+			}
+		}
+		i = j
+	}
+
+	output := make([]Phrase, 0, len(order))
+	for _, text := range order {
+		output = append(output, *phrases[text])
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].Weight > output[j].Weight
+	})
+	if topK > 0 && len(output) > topK {
+		output = output[:topK]
+	}
+	return output
+}
+
+// 构造共现图并计算每个候选分词的PageRank值，结果归一化到最大值为1
+func (t *TextRank) rank(segs []sego.Segment) map[string]float64 {
+	// graph[a][b]为分词a和b在窗口内的共现次数
+	graph := make(map[string]map[string]float64)
+	addEdge := func(a, b string) {
+		if graph[a] == nil {
+			graph[a] = make(map[string]float64)
+		}
+		graph[a][b]++
+	}
+	for i := range segs {
+		if !t.accept(&segs[i]) {
+			continue
+		}
+		a := segs[i].Token().Text()
+		if graph[a] == nil {
+			graph[a] = make(map[string]float64)
+		}
+		for j := i + 1; j < len(segs) && j < i+t.window; j++ {
+			if !t.accept(&segs[j]) {
+				continue
+			}
+			b := segs[j].Token().Text()
+			if a == b {
+				continue
+			}
+			addEdge(a, b)
+			addEdge(b, a)
+		}
+	}
+
+	// 每个分词的出边权重之和
+	outWeight := make(map[string]float64, len(graph))
+	for a, edges := range graph {
+		for _, w := range edges {
+			outWeight[a] += w
+		}
+	}
+
+	scores := make(map[string]float64, len(graph))
+	for a := range graph {
+		scores[a] = 1
+	}
+	for iter := 0; iter < maxIterations; iter++ {
+		maxDelta := 0.0
+		next := make(map[string]float64, len(graph))
+		for a, edges := range graph {
+			sum := 0.0
+			for b, w := range edges {
+				sum += w / outWeight[b] * scores[b]
+			}
+			next[a] = 1 - t.damping + t.damping*sum
+			maxDelta = math.Max(maxDelta, math.Abs(next[a]-scores[a]))
+		}
+		scores = next
+		if maxDelta < convergenceDelta {
+			break
+		}
+	}
+
+	maxScore := 0.0
+	for _, score := range scores {
+		maxScore = math.Max(maxScore, score)
+	}
+	if maxScore > 0 {
+		for a := range scores {
+			scores[a] /= maxScore
+		}
+	}
+	return scores
+}
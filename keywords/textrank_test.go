@@ -0,0 +1,42 @@
+package keywords
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTextRankPhrases(t *testing.T) {
+	segmenter := newTestSegmenter(t,
+		"机器 100 n",
+		"学习 100 v",
+		"改变 100 v",
+		"世界 100 n",
+		"推动 100 v",
+		"科学 100 n",
+		"帮助 100 v",
+		"医疗 100 n",
+	)
+	textRank := NewTextRank(segmenter)
+	text := []byte("机器学习 改变 世界。机器学习 推动 科学。机器学习 帮助 医疗")
+
+	weights := make(map[string]float64)
+	for _, keyword := range textRank.Extract(text, 0) {
+		weights[keyword.Text] = keyword.Weight
+	}
+	if len(weights) != 8 {
+		t.Fatalf("Extract() returned %d keywords, want 8", len(weights))
+	}
+
+	// 排名前三的分词中只有首尾相接的"机器"和"学习"合并为短语，中间隔着空格的不合并
+	phrases := textRank.ExtractPhrases(text, 0)
+	if len(phrases) != 1 {
+		t.Fatalf("ExtractPhrases() = %v, want one phrase", phrases)
+	}
+	phrase := phrases[0]
+	if phrase.Text != "机器学习" || phrase.Start != 0 || phrase.End != 4 {
+		t.Errorf("ExtractPhrases() = %q %d-%d, want 机器学习 0-4", phrase.Text, phrase.Start, phrase.End)
+	}
+	if want := weights["机器"] + weights["学习"]; math.Abs(phrase.Weight-want) > 1e-9 {
+		t.Errorf("phrase weight = %v, want %v", phrase.Weight, want)
+	}
+}
@@ -0,0 +1,146 @@
+package keywords
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crossgit/sego"
+)
+
+// 一个关键词及其权重
+type Keyword struct {
+	Text   string  `json:"text"`
+	Weight float64 `json:"weight"`
+}
+
+// 基于TF-IDF的关键词提取器
+//
+// 分词的IDF值优先从载入的IDF表中查找，表中没有时由分词器词典的词频推算：
+//	idf = ln(词典总词频 / 分词词频)
+// 词典中也没有的分词使用IDF表的中位数，没有IDF表时按词频为1计算。
+type TFIDF struct {
+	filter
+
+	idf       map[string]float64
+	medianIDF float64
+}
+
+// 新建使用segmenter分词的提取器
+func NewTFIDF(segmenter *sego.Segmenter) *TFIDF {
+	return &TFIDF{
+		filter: newFilter(segmenter),
+		idf:    make(map[string]float64),
+	}
+}
+
+// 从reader中载入IDF表，格式为每行一个"分词 IDF值"，可以多次载入
+func (t *TFIDF) LoadIDF(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			// 无效行
+			continue
+		}
+		idf, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			continue
+		}
+		t.idf[strings.ToLower(fields[0])] = idf
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	values := make([]float64, 0, len(t.idf))
+	for _, idf := range t.idf {
+		values = append(values, idf)
+	}
+	sort.Float64s(values)
+	if len(values) > 0 {
+		t.medianIDF = values[len(values)/2]
+	}
+	return nil
+}
+
+// 从文件中载入IDF表，多个文件名用","分隔
+func (t *TFIDF) LoadIDFFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		idfFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = t.LoadIDF(idfFile)
+		idfFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 返回分词的IDF值
+func (t *TFIDF) IDF(word string) float64 {
+	if idf, ok := t.idf[word]; ok {
+		return idf
+	}
+	dict := t.segmenter.Dictionary()
+	if dict != nil && dict.TotalFrequency() > 0 {
+		if token := dict.Lookup(word); token != nil {
+			return math.Log(float64(dict.TotalFrequency()) / float64(token.Frequency()))
+		}
+	}
+	if t.medianIDF > 0 {
+		return t.medianIDF
+	}
+	if dict != nil && dict.TotalFrequency() > 0 {
+		return math.Log(float64(dict.TotalFrequency()))
+	}
+	return 1
+}
+
+// 对文本分词并返回权重最高的topK个关键词，topK不大于零时返回全部
+func (t *TFIDF) Extract(text []byte, topK int) []Keyword {
+	return t.ExtractSegments(t.segmenter.Segment(text), topK)
+}
+
+// 从分词结果中返回权重最高的topK个关键词，topK不大于零时返回全部
+func (t *TFIDF) ExtractSegments(segs []sego.Segment, topK int) []Keyword {
+	freq := make(map[string]int)
+	total := 0
+	for i := range segs {
+		if !t.accept(&segs[i]) {
+			continue
+		}
+		freq[segs[i].Token().Text()]++
+		total++
+	}
+
+	output := make([]Keyword, 0, len(freq))
+	for word, count := range freq {
+		output = append(output, Keyword{
+			Text:   word,
+			Weight: float64(count) / float64(total) * t.IDF(word),
+		})
+	}
+	return topKeywords(output, topK)
+}
+
+// 按权重从高到低排序并截取前topK个，权重相同时按文本排序以保证结果稳定
+func topKeywords(keywords []Keyword, topK int) []Keyword {
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Weight != keywords[j].Weight {
+			return keywords[i].Weight > keywords[j].Weight
+		}
+		return keywords[i].Text < keywords[j].Text
+	})
+	if topK > 0 && len(keywords) > topK {
+		keywords = keywords[:topK]
+	}
+	return keywords
+}
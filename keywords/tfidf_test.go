@@ -0,0 +1,65 @@
+package keywords
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crossgit/sego"
+)
+
+func newTestSegmenter(t *testing.T, lines ...string) *sego.Segmenter {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "dict.txt")
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+	return segmenter
+}
+
+func keywordTexts(keywords []Keyword) []string {
+	output := make([]string, len(keywords))
+	for i, k := range keywords {
+		output[i] = k.Text
+	}
+	return output
+}
+
+func TestTFIDFTopK(t *testing.T) {
+	segmenter := newTestSegmenter(t,
+		"苹果 100 n",
+		"香蕉 100 n",
+		"橙子 100 n",
+		"西瓜 50 n",
+	)
+	tfidf := NewTFIDF(segmenter)
+	if err := tfidf.LoadIDF(strings.NewReader("苹果 1.0\n香蕉 3.0\n橙子 2.0\n无效行\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 苹果 2/4*1.0=0.5，香蕉 1/4*3.0=0.75，橙子 1/4*2.0=0.5，权重相同时按文本排序
+	keywords := tfidf.Extract([]byte("苹果 香蕉 苹果 橙子"), 0)
+	want := []Keyword{{"香蕉", 0.75}, {"橙子", 0.5}, {"苹果", 0.5}}
+	if !reflect.DeepEqual(keywords, want) {
+		t.Errorf("Extract() = %v, want %v", keywords, want)
+	}
+	if got := keywordTexts(tfidf.Extract([]byte("苹果 香蕉 苹果 橙子"), 2)); !reflect.DeepEqual(got, []string{"香蕉", "橙子"}) {
+		t.Errorf("Extract(topK=2) = %q, want [香蕉 橙子]", got)
+	}
+
+	// IDF表中没有的分词由词典词频推算
+	if got, want := tfidf.IDF("西瓜"), math.Log(350.0/50.0); math.Abs(got-want) > 1e-9 {
+		t.Errorf("IDF(西瓜) = %v, want %v", got, want)
+	}
+	// 词典中也没有时使用IDF表的中位数
+	if got := tfidf.IDF("葡萄"); got != 2.0 {
+		t.Errorf("IDF(葡萄) = %v, want 2", got)
+	}
+}
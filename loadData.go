@@ -18,88 +18,63 @@ import (
 //
 // 词典的格式为（每个分词一行）：
 //	分词文本 频率 词性
+// 一个分词有多个词性时可以在行尾依次追加"频率 词性"，比如
+//	发展 800 v 200 vn
+// 此时分词的频率为各词性频率之和，第一个词性为主词性，行尾没有词性的频率被忽略。
+// 同一分词在多行或多个词典中出现时，词频和主词性以最先载入的为准，后出现的新词性
+// 追加到该分词上。
+// 频率之后以"#"开头的字段及其后的内容为注释，比如
+//	区块链 25 n # 新词发现的得分
+// 分词文本本身可以以"#"开头，比如"#话题 10 n"。
+// 文件无法读取时调用log.Fatalf退出程序，需要处理错误时使用LoadDictionaryE。
 func (seg *Segmenter) LoadDictionary(files string) {
-	seg.dict = NewDictionary()
+	if err := seg.LoadDictionaryE(files); err != nil {
+		log.Fatalf("%s\n", err)
+	}
+}
+
+// 与LoadDictionary相同，但文件无法读取时返回错误而不是退出程序，此时分词器的词典保持不变
+func (seg *Segmenter) LoadDictionaryE(files string) error {
+	dict := NewDictionary()
 	for _, file := range strings.Split(files, ",") {
 		log.Printf("载入sego词典 %s", file)
-		dictFile, err := os.Open(file)
-		defer dictFile.Close()
-		if err != nil {
-			log.Fatalf("无法载入字典文件 \"%s\" \n", file)
-		}
-
-		reader := bufio.NewReader(dictFile)
-		var text string
-		var freqText string
-		var frequency int
-		var pos string
-
-		// 逐行读入分词
-		for {
-			size, _ := fmt.Fscanln(reader, &text, &freqText, &pos)
-
-			if size == 0 {
-				// 文件结束
-				break
-			} else if size < 2 {
-				// 无效行
-				continue
-			} else if size == 2 {
-				// 没有词性标注时设为空字符串
-				pos = ""
-			}
-
-			// 解析词频
-			var err error
-			frequency, err = strconv.Atoi(freqText)
-			if err != nil {
-				continue
-			}
-
-			// 过滤频率太小的词
-			if frequency < minTokenFrequency {
-				continue
-			}
-
-			// 将分词添加到字典中
-			words := splitTextToWords([]byte(text))
-			token := Token{text: words, frequency: frequency, pos: pos}
-			seg.dict.addToken(token)
+		if err := seg.loadDictionaryFile(dict, file); err != nil {
+			return err
 		}
 	}
+	seg.dict = dict
+	seg.prepareDictionary()
+	log.Println("sego词典载入完毕")
+	return nil
+}
 
-	// 计算每个分词的路径值，路径值含义见Token结构体的注释
-	logTotalFrequency := float32(math.Log2(float64(seg.dict.totalFrequency)))
-	for i := range seg.dict.tokens {
-		token := &seg.dict.tokens[i]
-		token.distance = logTotalFrequency - float32(math.Log2(float64(token.frequency)))
+// 把一个词典文件中的分词加入dict
+func (seg *Segmenter) loadDictionaryFile(dict *Dictionary, file string) error {
+	dictFile, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("无法载入字典文件 \"%s\": %s", file, err)
 	}
+	defer dictFile.Close()
 
-	// 对每个分词进行细致划分，用于搜索引擎模式，该模式用法见Token结构体的注释。
-	for i := range seg.dict.tokens {
-		token := &seg.dict.tokens[i]
-		segments := seg.cutJump(token.text, true)
+	scanner := bufio.NewScanner(dictFile)
 
-		// 计算需要添加的子分词数目
-		numTokensToAdd := 0
-		for iToken := 0; iToken < len(segments); iToken++ {
-			if len(segments[iToken].token.text) > 0 {
-				numTokensToAdd++
-			}
+	// 逐行读入分词
+	for scanner.Scan() {
+		text, tags := parseDictionaryLine(scanner.Text())
+		if len(tags) == 0 {
+			// 无效行
+			continue
 		}
-		token.segments = make([]*Segment, numTokensToAdd)
 
-		// 添加子分词
-		iSegmentsToAdd := 0
-		for iToken := 0; iToken < len(segments); iToken++ {
-			if len(segments[iToken].token.text) > 0 {
-				token.segments[iSegmentsToAdd] = &segments[iToken]
-				iSegmentsToAdd++
-			}
+		// 将分词添加到字典中
+		if token, ok := seg.newDictionaryToken(text, tags); ok {
+			dict.addToken(token)
 		}
 	}
-
-	log.Println("sego词典载入完毕")
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("无法读取字典文件 \"%s\": %s", file, err)
+	}
+	return nil
 }
 
 // LoadDictionaryFromDB 从数据库导入词库
@@ -112,18 +87,18 @@ func (seg *Segmenter) LoadDictionaryFromDB(infos []map[string]string) {
 			continue
 		}
 
-		// 过滤频率太小的词
-		if frequency < minTokenFrequency {
-			continue
+		// 将分词添加到字典中，同一分词的多个词性可以分多行给出
+		tags := []TokenPos{{Pos: v["pos"], Frequency: frequency}}
+		if token, ok := seg.newDictionaryToken(v["text"], tags); ok {
+			seg.dict.addToken(token)
 		}
-
-		// 将分词添加到字典中
-		words := splitTextToWords([]byte(v["text"]))
-
-		token := Token{text: words, frequency: frequency, pos: v["pos"]}
-		seg.dict.addToken(token)
 	}
+	seg.prepareDictionary()
+	log.Println("sego词典载入完毕")
+}
 
+// 词典中的分词全部加入后计算路径值和子分词
+func (seg *Segmenter) prepareDictionary() {
 	// 计算每个分词的路径值，路径值含义见Token结构体的注释
 	logTotalFrequency := float32(math.Log2(float64(seg.dict.totalFrequency)))
 	for i := range seg.dict.tokens {
@@ -155,5 +130,71 @@ func (seg *Segmenter) LoadDictionaryFromDB(infos []map[string]string) {
 		}
 	}
 
-	log.Println("sego词典载入完毕")
+	if seg.pinyin != nil {
+		seg.dict.annotatePinyin(seg.pinyin)
+	}
+}
+
+// 用分词器的标注集校验词性，不合法的词性记录日志后返回false
+func (seg *Segmenter) validPos(pos string) bool {
+	if seg.tagSet == nil {
+		return true
+	}
+	if err := seg.tagSet.Validate(pos); err != nil {
+		log.Printf("忽略词典条目：%s", err)
+		return false
+	}
+	return true
+}
+
+// 解析词典中的一行，返回分词文本和各词性的频率，无效行返回空的词性列表
+func parseDictionaryLine(line string) (text string, tags []TokenPos) {
+	fields := strings.Fields(line)
+	for i := 2; i < len(fields); i++ {
+		// 分词文本和频率不会是注释
+		if strings.HasPrefix(fields[i], "#") {
+			fields = fields[:i]
+			break
+		}
+	}
+	if len(fields) < 2 {
+		return
+	}
+	text = fields[0]
+	for i := 1; i < len(fields); i += 2 {
+		frequency, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return text, nil
+		}
+		// 只有一个频率、没有词性标注时设为空字符串，多词性行尾多出的频率忽略
+		pos := ""
+		if i+1 < len(fields) {
+			pos = fields[i+1]
+		} else if i > 1 {
+			break
+		}
+		tags = append(tags, TokenPos{Pos: pos, Frequency: frequency})
+	}
+	return
+}
+
+// 由分词文本和词性列表生成词典分词，过滤不合法的词性和频率太小的词
+func (seg *Segmenter) newDictionaryToken(text string, tags []TokenPos) (Token, bool) {
+	validTags := make([]TokenPos, 0, len(tags))
+	frequency := 0
+	for _, tag := range tags {
+		if !seg.validPos(tag.Pos) {
+			continue
+		}
+		validTags = append(validTags, tag)
+		frequency += tag.Frequency
+	}
+
+	// 过滤频率太小的词
+	if len(validTags) == 0 || frequency < minTokenFrequency {
+		return Token{}, false
+	}
+
+	words := splitTextToWords([]byte(text))
+	return Token{text: words, frequency: frequency, pos: validTags[0].Pos, tags: validTags}, true
 }
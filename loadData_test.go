@@ -0,0 +1,141 @@
+package sego
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDictionaryLine(t *testing.T) {
+	cases := []struct {
+		line string
+		text string
+		tags []TokenPos
+	}{
+		{"中国 100 ns", "中国", []TokenPos{{"ns", 100}}},
+		{"人民 50", "人民", []TokenPos{{"", 50}}},
+		{"发展 800 v 200 vn", "发展", []TokenPos{{"v", 800}, {"vn", 200}}},
+		{"发展\t800  v\t200 vn ", "发展", []TokenPos{{"v", 800}, {"vn", 200}}},
+		// 多词性行尾没有词性的频率被忽略
+		{"发展 800 v 200", "发展", []TokenPos{{"v", 800}}},
+		{"发展 800 v 200 vn 10", "发展", []TokenPos{{"v", 800}, {"vn", 200}}},
+		{"区块链 25 n # score=1.0", "区块链", []TokenPos{{"n", 25}}},
+		{"区块链 25 # score=1.0", "区块链", []TokenPos{{"", 25}}},
+		{"#话题 10 n", "#话题", []TokenPos{{"n", 10}}},
+		{"#话题 10 n #注释", "#话题", []TokenPos{{"n", 10}}},
+		// 无效行
+		{"", "", nil},
+		{"中国", "", nil},
+		{"中国 abc n", "中国", nil},
+		{"发展 800 v abc vn", "发展", nil},
+	}
+	for _, c := range cases {
+		text, tags := parseDictionaryLine(c.line)
+		if len(c.tags) == 0 {
+			if len(tags) != 0 {
+				t.Errorf("parseDictionaryLine(%q) = %v, want no tags", c.line, tags)
+			}
+			continue
+		}
+		if text != c.text || !reflect.DeepEqual(tags, c.tags) {
+			t.Errorf("parseDictionaryLine(%q) = %q %v, want %q %v", c.line, text, tags, c.text, c.tags)
+		}
+	}
+}
+
+func TestLoadDictionary(t *testing.T) {
+	dir := t.TempDir()
+	user := writeTestFile(t, dir, "user.txt",
+		"中国 100 ns",
+		"发展 800 v 200 vn",
+		"人民 50",
+		"无效 abc n",
+		"",
+		"罕见 1 n",
+		"边界 2 n",
+		"#话题 10 n # 注释",
+	)
+	common := writeTestFile(t, dir, "common.txt",
+		"中国 30 n",
+		"发展 5 v",
+		"罕见 1 a",
+		"共和国 10 n",
+	)
+
+	var seg Segmenter
+	seg.LoadDictionary(user + "," + common)
+	dict := seg.Dictionary()
+
+	cases := []struct {
+		text      string
+		frequency int
+		pos       string
+		tags      []TokenPos
+	}{
+		// 先载入的词典优先，后载入的新词性追加在后面
+		{"中国", 100, "ns", []TokenPos{{"ns", 100}, {"n", 30}}},
+		// 已有的词性不重复追加
+		{"发展", 1000, "v", []TokenPos{{"v", 800}, {"vn", 200}}},
+		{"人民", 50, "", []TokenPos{{"", 50}}},
+		{"边界", 2, "n", []TokenPos{{"n", 2}}},
+		{"共和国", 10, "n", []TokenPos{{"n", 10}}},
+		{"#话题", 10, "n", []TokenPos{{"n", 10}}},
+	}
+	for _, c := range cases {
+		token := dict.Lookup(c.text)
+		if token == nil {
+			t.Errorf("Lookup(%q) = nil", c.text)
+			continue
+		}
+		if token.Frequency() != c.frequency || token.Pos() != c.pos || !reflect.DeepEqual(token.PosTags(), c.tags) {
+			t.Errorf("Lookup(%q) = %d %q %v, want %d %q %v", c.text,
+				token.Frequency(), token.Pos(), token.PosTags(), c.frequency, c.pos, c.tags)
+		}
+	}
+
+	// 无效行和频率小于minTokenFrequency的分词不载入
+	for _, text := range []string{"无效", "罕见"} {
+		if token := dict.Lookup(text); token != nil {
+			t.Errorf("Lookup(%q) = %v, want nil", text, token)
+		}
+	}
+	if dict.NumTokens() != len(cases) {
+		t.Errorf("NumTokens() = %d, want %d", dict.NumTokens(), len(cases))
+	}
+	if want := int64(100 + 1000 + 50 + 2 + 10 + 10); dict.TotalFrequency() != want {
+		t.Errorf("TotalFrequency() = %d, want %d", dict.TotalFrequency(), want)
+	}
+}
+
+func TestLoadDictionaryError(t *testing.T) {
+	dir := t.TempDir()
+	var seg Segmenter
+	if err := seg.LoadDictionaryE(writeTestFile(t, dir, "dict.txt", "中国 100 ns")); err != nil {
+		t.Fatal(err)
+	}
+	dict := seg.Dictionary()
+
+	// 文件无法读取时返回错误，原有的词典保持不变
+	missing := filepath.Join(dir, "missing.txt")
+	if err := seg.LoadDictionaryE(writeTestFile(t, dir, "user.txt", "人民 100 n") + "," + missing); err == nil {
+		t.Error("LoadDictionaryE() with missing file returned nil error")
+	}
+	if seg.Dictionary() != dict || seg.Dictionary().Lookup("人民") != nil {
+		t.Error("LoadDictionaryE() replaced the dictionary after an error")
+	}
+
+	if err := seg.LoadStopWordsE(missing); err == nil {
+		t.Error("LoadStopWordsE() with missing file returned nil error")
+	}
+}
+
+func writeTestFile(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
@@ -0,0 +1,151 @@
+package sego
+
+// 两个相交的分词词性不同时的处理方式
+type OverlapPolicy int
+
+const (
+	// 后一个分词替换前一个分词（SegmentsMergeOutput的原有行为）
+	OverlapReplace OverlapPolicy = iota
+
+	// 保留前一个分词，丢弃后一个分词
+	OverlapKeepFirst
+
+	// 把前一个分词延伸到后一个分词的结尾，词性保持不变
+	OverlapExtend
+)
+
+// 全切结果的合并策略
+//
+// Merge按输入顺序（起始位置从小到大）逐个处理全切结果，把每个分词nseg与
+// 当前输出的最后一个分词last比较，分五种情况：
+//
+//	1. 分离：nseg.Start > last.End
+//	   last是删除词时被nseg替换，否则追加nseg。
+//	2. 相交且nseg是删除词：last.End > nseg.Start且nseg.End > last.End
+//	   DeletionPropagates为true时last的词性改为nseg的词性，即last也变成删除词；
+//	   否则忽略nseg。
+//	3. 相交且词性相同：last不是删除词、nseg.Pos == last.Pos且ConcatOverlapping为true
+//	   last延伸到nseg的结尾，文本追加nseg超出last的部分，比如ABCD/n + CDEF/n -> ABCDEF/n。
+//	4. 相交的其它情况：按Overlap处理。
+//	5. 相邻：last.End == nseg.Start且nseg.End > last.End
+//	   nseg是删除词时忽略；词性相同且ConcatAdjacent为true时拼接，
+//	   比如ABCD/n + EFGH/n -> ABCDEFGH/n；否则追加nseg。
+// 被last完全包含的nseg（nseg.End <= last.End）总是被忽略。
+//
+// 删除词由Tags中的TagDeletion类别决定，默认标注集中即以"d"开头的词性。按以上规则
+// 删除词只在后面紧跟分离的分词时才会被去掉，出现在末尾或后接相邻分词的删除词会留在
+// 输出中；DropDeletions为true时在最后去掉输出中所有的删除词。
+type MergePolicy struct {
+	// 判断删除词使用的标注集，为nil时使用DefaultTagSet
+	Tags *TagSet
+
+	// 相交且词性不同时的处理方式
+	Overlap OverlapPolicy
+
+	// 相交的词性相同的分词是否拼接
+	ConcatOverlapping bool
+
+	// 相邻的词性相同的分词是否拼接
+	ConcatAdjacent bool
+
+	// 与前一个分词相交的删除词是否把前一个分词也变成删除词
+	DeletionPropagates bool
+
+	// 是否从输出中去掉所有删除词
+	DropDeletions bool
+}
+
+// 返回SegmentsMergeOutput使用的合并策略
+func DefaultMergePolicy() MergePolicy {
+	return MergePolicy{
+		Tags:               DefaultTagSet,
+		Overlap:            OverlapReplace,
+		ConcatOverlapping:  true,
+		ConcatAdjacent:     true,
+		DeletionPropagates: true,
+	}
+}
+
+// 按策略合并全切结果
+func (policy MergePolicy) Merge(segs []CutAll) []OutputSingle {
+	output := make([]OutputSingle, 0)
+	if len(segs) == 0 {
+		return output
+	}
+	tags := policy.Tags
+	if tags == nil {
+		tags = DefaultTagSet
+	}
+
+	output = append(output, cutAllToOutput(segs[0]))
+	for i := 1; i < len(segs); i++ {
+		last := &output[len(output)-1]
+		nseg := segs[i]
+		lastDeletion := tags.Is(last.Pos, TagDeletion)
+		nsegDeletion := tags.Is(nseg.Pos, TagDeletion)
+
+		switch {
+		case nseg.Start > last.End:
+			// 分离
+			if lastDeletion {
+				*last = cutAllToOutput(nseg)
+			} else {
+				output = append(output, cutAllToOutput(nseg))
+			}
+		case last.End > nseg.Start && nseg.End > last.End:
+			// 相交
+			if nsegDeletion {
+				if policy.DeletionPropagates {
+					last.Pos = nseg.Pos
+				}
+				continue
+			}
+			if !lastDeletion && nseg.Pos == last.Pos && policy.ConcatOverlapping {
+				extendOutput(last, nseg)
+				continue
+			}
+			switch policy.Overlap {
+			case OverlapReplace:
+				*last = cutAllToOutput(nseg)
+			case OverlapExtend:
+				extendOutput(last, nseg)
+			}
+		case last.End == nseg.Start && nseg.End > last.End:
+			// 相邻
+			if nsegDeletion {
+				continue
+			}
+			if nseg.Pos == last.Pos && policy.ConcatAdjacent {
+				extendOutput(last, nseg)
+				continue
+			}
+			output = append(output, cutAllToOutput(nseg))
+		}
+	}
+
+	if policy.DropDeletions {
+		kept := output[:0]
+		for _, o := range output {
+			if !tags.Is(o.Pos, TagDeletion) {
+				kept = append(kept, o)
+			}
+		}
+		output = kept
+	}
+	return output
+}
+
+func cutAllToOutput(seg CutAll) OutputSingle {
+	return OutputSingle{
+		Start:  seg.Start,
+		End:    seg.End,
+		NToken: seg.Token,
+		Pos:    seg.Pos,
+	}
+}
+
+// 把last延伸到nseg的结尾，文本追加nseg超出last的部分
+func extendOutput(last *OutputSingle, nseg CutAll) {
+	last.NToken += string([]rune(nseg.Token)[last.End-nseg.Start:])
+	last.End = nseg.End
+}
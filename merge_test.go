@@ -0,0 +1,184 @@
+package sego
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+// 生成从start开始的全切分词
+func cut(start int, token, pos string) CutAll {
+	return CutAll{Start: start, End: start + utf8.RuneCountInString(token), Token: token, Pos: pos}
+}
+
+func formatOutput(output []OutputSingle) []string {
+	formatted := make([]string, len(output))
+	for i, o := range output {
+		formatted[i] = fmt.Sprintf("%d-%d %s/%s", o.Start, o.End, o.NToken, o.Pos)
+	}
+	return formatted
+}
+
+func TestMergePolicy(t *testing.T) {
+	withPolicy := func(change func(*MergePolicy)) MergePolicy {
+		policy := DefaultMergePolicy()
+		change(&policy)
+		return policy
+	}
+	noPropagation := withPolicy(func(p *MergePolicy) { p.DeletionPropagates = false })
+	noConcatOverlapping := withPolicy(func(p *MergePolicy) { p.ConcatOverlapping = false })
+	noConcatAdjacent := withPolicy(func(p *MergePolicy) { p.ConcatAdjacent = false })
+	keepFirst := withPolicy(func(p *MergePolicy) { p.Overlap = OverlapKeepFirst })
+	extend := withPolicy(func(p *MergePolicy) { p.Overlap = OverlapExtend })
+	dropDeletions := withPolicy(func(p *MergePolicy) { p.DropDeletions = true })
+
+	cases := []struct {
+		name   string
+		policy MergePolicy
+		segs   []CutAll
+		want   []string
+	}{
+		{"empty", DefaultMergePolicy(), nil, []string{}},
+		{"single", DefaultMergePolicy(), []CutAll{cut(0, "中国", "ns")}, []string{"0-2 中国/ns"}},
+
+		// 1. 分离
+		{"separate", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "n"), cut(3, "DE", "v")},
+			[]string{"0-2 AB/n", "3-5 DE/v"}},
+		{"separate after deletion", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "d"), cut(3, "DE", "v")},
+			[]string{"3-5 DE/v"}},
+
+		// 2. 相交且nseg是删除词
+		{"overlapping deletion propagates", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "d")},
+			[]string{"0-4 ABCD/d"}},
+		{"overlapping deletion ignored", noPropagation,
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "d")},
+			[]string{"0-4 ABCD/n"}},
+		{"propagated deletion replaced by separate", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "d"), cut(7, "HI", "v")},
+			[]string{"7-9 HI/v"}},
+
+		// 3. 相交且词性相同
+		{"overlapping same pos", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "n")},
+			[]string{"0-6 ABCDEF/n"}},
+		{"overlapping same pos without concat", noConcatOverlapping,
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "n")},
+			[]string{"2-6 CDEF/n"}},
+		{"overlapping same pos after deletion", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "d"), cut(2, "CDEF", "d")},
+			[]string{"0-4 ABCD/d"}},
+
+		// 4. 相交的其它情况
+		{"overlap replace", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "v")},
+			[]string{"2-6 CDEF/v"}},
+		{"overlap keep first", keepFirst,
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "v")},
+			[]string{"0-4 ABCD/n"}},
+		{"overlap extend", extend,
+			[]CutAll{cut(0, "ABCD", "n"), cut(2, "CDEF", "v")},
+			[]string{"0-6 ABCDEF/n"}},
+
+		// 5. 相邻
+		{"adjacent same pos", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "n"), cut(2, "CD", "n")},
+			[]string{"0-4 ABCD/n"}},
+		{"adjacent same pos without concat", noConcatAdjacent,
+			[]CutAll{cut(0, "AB", "n"), cut(2, "CD", "n")},
+			[]string{"0-2 AB/n", "2-4 CD/n"}},
+		{"adjacent different pos", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "n"), cut(2, "CD", "v")},
+			[]string{"0-2 AB/n", "2-4 CD/v"}},
+		{"adjacent deletion", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "n"), cut(2, "CD", "d")},
+			[]string{"0-2 AB/n"}},
+
+		// 被完全包含
+		{"contained", DefaultMergePolicy(),
+			[]CutAll{cut(0, "ABCD", "n"), cut(1, "BC", "v"), cut(2, "CD", "n")},
+			[]string{"0-4 ABCD/n"}},
+
+		// 删除词留在末尾时由DropDeletions去掉
+		{"trailing deletion kept", DefaultMergePolicy(),
+			[]CutAll{cut(0, "AB", "n"), cut(3, "DE", "d")},
+			[]string{"0-2 AB/n", "3-5 DE/d"}},
+		{"trailing deletion dropped", dropDeletions,
+			[]CutAll{cut(0, "AB", "n"), cut(3, "DE", "d")},
+			[]string{"0-2 AB/n"}},
+		{"propagated deletion dropped", dropDeletions,
+			[]CutAll{cut(0, "AB", "v"), cut(3, "DEFG", "n"), cut(5, "FGH", "d")},
+			[]string{"0-2 AB/v"}},
+	}
+	for _, c := range cases {
+		got := formatOutput(c.policy.Merge(c.segs))
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Merge() = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDefaultMergePolicyMatchesLegacy(t *testing.T) {
+	inputs := [][]CutAll{
+		{cut(0, "中国", "ns"), cut(0, "中国人", "n"), cut(1, "国人", "n"), cut(2, "人民", "n"), cut(4, "万岁", "v")},
+		{cut(0, "ABCD", "n"), cut(2, "CDEF", "d"), cut(4, "EF", "n"), cut(7, "HI", "v")},
+		{cut(0, "AB", "d"), cut(2, "CD", "n"), cut(2, "CDE", "v"), cut(5, "FG", "v"), cut(6, "GH", "v")},
+		{cut(0, "ABCD", "n"), cut(1, "BC", "v"), cut(3, "DE", "n"), cut(5, "FG", "d"), cut(9, "JK", "n")},
+		{cut(0, "AB", "n"), cut(2, "CD", "n"), cut(3, "DEF", "v"), cut(6, "GH", "v"), cut(7, "HI", "d")},
+	}
+	for _, segs := range inputs {
+		want := legacySegmentsMergeOutput(segs)
+		if got := DefaultMergePolicy().Merge(segs); !reflect.DeepEqual(got, want) {
+			t.Errorf("Merge(%v) = %q, want %q", segs, formatOutput(got), formatOutput(want))
+		}
+		if got := SegmentsMergeOutput(segs); !reflect.DeepEqual(got, want) {
+			t.Errorf("SegmentsMergeOutput(%v) = %q, want %q", segs, formatOutput(got), formatOutput(want))
+		}
+	}
+}
+
+// 改为MergePolicy之前SegmentsMergeOutput的实现
+func legacySegmentsMergeOutput(segs []CutAll) []OutputSingle {
+	output := make([]OutputSingle, 0)
+	if len(segs) == 0 {
+		return output
+	}
+	output = append(output, cutAllToOutput(segs[0]))
+	for i := 0; i < len(segs); i++ {
+		lastOne := output[len(output)-1]
+		nseg := segs[i]
+		if nseg.Start > lastOne.End {
+			if lastOne.Pos[:1] == "d" {
+				output[len(output)-1] = cutAllToOutput(nseg)
+			} else {
+				output = append(output, cutAllToOutput(nseg))
+			}
+			continue
+		}
+		if lastOne.End > nseg.Start && nseg.End > lastOne.End {
+			if nseg.Pos[:1] == "d" {
+				output[len(output)-1].Pos = nseg.Pos
+				continue
+			}
+			if lastOne.Pos[:1] != "d" && nseg.Pos == lastOne.Pos {
+				output[len(output)-1].End = nseg.End
+				output[len(output)-1].NToken = lastOne.NToken + string([]rune(nseg.Token)[lastOne.End-nseg.Start:])
+				continue
+			}
+			output[len(output)-1] = cutAllToOutput(nseg)
+			continue
+		}
+		if lastOne.End == nseg.Start && nseg.End > lastOne.End && nseg.Pos[:1] != "d" {
+			if nseg.Pos == lastOne.Pos {
+				output[len(output)-1].End = nseg.End
+				output[len(output)-1].NToken = lastOne.NToken + string([]rune(nseg.Token)[lastOne.End-nseg.Start:])
+				continue
+			}
+			output = append(output, cutAllToOutput(nseg))
+		}
+	}
+	return output
+}
@@ -0,0 +1,272 @@
+// 从未分词的文本中无监督地发现新词
+//
+// 统计文本中连续汉字的n元组，用两个指标判断一个n元组是否成词：
+//	凝固度：n元组所有二分切法中点互信息（PMI）的最小值，
+//		PMI(ab) = ln(p(ab) / (p(a) * p(b)))，p为出现次数除以总字数
+//	自由度：n元组左邻字和右邻字的信息熵中较小的一个，文本边界上每次出现
+//		都算作一个不同的邻字
+// 凝固度高说明内部结合紧密，自由度高说明能出现在多种上下文中。两个指标都超过
+// 阈值、且不在词典中的n元组作为候选新词，得分为凝固度与自由度之积。
+package newwords
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/crossgit/sego"
+)
+
+const (
+	defaultMaxLength  = 4
+	defaultMinCount   = 5
+	defaultMinPMI     = 3.0
+	defaultMinEntropy = 1.0
+)
+
+// 一个候选新词
+type Candidate struct {
+	Text  string
+	Count int
+
+	// 凝固度
+	PMI float64
+
+	// 左右邻字的信息熵
+	LeftEntropy  float64
+	RightEntropy float64
+
+	// 得分，即PMI乘以左右信息熵中较小的一个
+	Score float64
+}
+
+// n元组的统计量
+type ngram struct {
+	count int
+
+	// 左右邻字的出现次数，文本边界不计入
+	left  map[rune]int
+	right map[rune]int
+
+	// 出现在文本边界上的次数
+	leftBoundary  int
+	rightBoundary int
+}
+
+// 新词发现器
+type Discoverer struct {
+	maxLength  int
+	minCount   int
+	minPMI     float64
+	minEntropy float64
+
+	// 长度为1到maxLength的n元组，长度大于1的才记录邻字
+	ngrams map[string]*ngram
+
+	// 统计过的总字数
+	total int
+}
+
+// 新建新词发现器，默认候选词最长4个字、至少出现5次、凝固度不小于3、自由度不小于1
+func NewDiscoverer() *Discoverer {
+	return &Discoverer{
+		maxLength:  defaultMaxLength,
+		minCount:   defaultMinCount,
+		minPMI:     defaultMinPMI,
+		minEntropy: defaultMinEntropy,
+		ngrams:     make(map[string]*ngram),
+	}
+}
+
+// 设置候选词的最大字数，需在Add之前调用
+func (d *Discoverer) SetMaxLength(length int) {
+	if length >= 2 {
+		d.maxLength = length
+	}
+}
+
+// 设置候选词的最少出现次数
+func (d *Discoverer) SetMinCount(count int) {
+	d.minCount = count
+}
+
+// 设置凝固度的阈值
+func (d *Discoverer) SetMinPMI(pmi float64) {
+	d.minPMI = pmi
+}
+
+// 设置自由度的阈值
+func (d *Discoverer) SetMinEntropy(entropy float64) {
+	d.minEntropy = entropy
+}
+
+// 统计一段文本，非汉字字符把文本分成若干段，n元组不跨段
+func (d *Discoverer) Add(text []byte) {
+	runes := []rune(string(text))
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && unicode.Is(unicode.Han, runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			d.addRun(runes[start:i])
+			start = -1
+		}
+	}
+}
+
+// 统计一段连续的汉字
+func (d *Discoverer) addRun(run []rune) {
+	d.total += len(run)
+	for i := range run {
+		for length := 1; length <= d.maxLength && i+length <= len(run); length++ {
+			key := string(run[i : i+length])
+			g, ok := d.ngrams[key]
+			if !ok {
+				g = &ngram{}
+				if length > 1 {
+					g.left = make(map[rune]int)
+					g.right = make(map[rune]int)
+				}
+				d.ngrams[key] = g
+			}
+			g.count++
+			if length == 1 {
+				continue
+			}
+			if i > 0 {
+				g.left[run[i-1]]++
+			} else {
+				g.leftBoundary++
+			}
+			if i+length < len(run) {
+				g.right[run[i+length]]++
+			} else {
+				g.rightBoundary++
+			}
+		}
+	}
+}
+
+// 逐行统计reader中的文本
+func (d *Discoverer) Scan(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		d.Add(scanner.Bytes())
+	}
+	return scanner.Err()
+}
+
+// 统计文件中的文本，多个文件名用","分隔
+func (d *Discoverer) ScanFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		textFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = d.Scan(textFile)
+		textFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 返回候选新词，按得分从高到低排列，dict中已有的分词被去掉，dict为nil时不过滤
+func (d *Discoverer) Candidates(dict *sego.Dictionary) []Candidate {
+	output := make([]Candidate, 0)
+	for text, g := range d.ngrams {
+		if g.left == nil || g.count < d.minCount {
+			continue
+		}
+		if dict != nil && dict.Lookup(text) != nil {
+			continue
+		}
+		pmi := d.pmi([]rune(text), g.count)
+		if pmi < d.minPMI {
+			continue
+		}
+		left := entropy(g.left, g.leftBoundary, g.count)
+		right := entropy(g.right, g.rightBoundary, g.count)
+		freedom := math.Min(left, right)
+		if freedom < d.minEntropy {
+			continue
+		}
+		output = append(output, Candidate{
+			Text:         text,
+			Count:        g.count,
+			PMI:          pmi,
+			LeftEntropy:  left,
+			RightEntropy: right,
+			Score:        pmi * freedom,
+		})
+	}
+	sort.Slice(output, func(i, j int) bool {
+		if output[i].Score != output[j].Score {
+			return output[i].Score > output[j].Score
+		}
+		return output[i].Text < output[j].Text
+	})
+	return output
+}
+
+// 计算n元组所有二分切法中PMI的最小值
+func (d *Discoverer) pmi(runes []rune, count int) float64 {
+	total := float64(d.total)
+	p := float64(count) / total
+	minPMI := math.Inf(1)
+	for i := 1; i < len(runes); i++ {
+		a := d.ngrams[string(runes[:i])].count
+		b := d.ngrams[string(runes[i:])].count
+		pmi := math.Log(p / (float64(a) / total * float64(b) / total))
+		if pmi < minPMI {
+			minPMI = pmi
+		}
+	}
+	return minPMI
+}
+
+// 计算邻字的信息熵，文本边界上的每次出现都算作一个只出现一次的邻字
+func entropy(neighbors map[rune]int, boundary, total int) float64 {
+	n := float64(total)
+	h := 0.0
+	for _, count := range neighbors {
+		p := float64(count) / n
+		h -= p * math.Log(p)
+	}
+	if boundary > 0 {
+		p := 1 / n
+		h -= float64(boundary) * p * math.Log(p)
+	}
+	return h
+}
+
+// 把候选新词写成LoadDictionary格式的词典，频率为出现次数，词性为pos，
+// 行尾的注释记录各项指标，比如
+//	区块链 25 n # score=12.3456 pmi=6.1728 left=2.0000 right=2.3026
+func WriteCandidates(w io.Writer, candidates []Candidate, pos string) error {
+	buf := bufio.NewWriter(w)
+	for _, c := range candidates {
+		buf.WriteString(c.Text)
+		buf.WriteByte(' ')
+		buf.WriteString(strconv.Itoa(c.Count))
+		if pos != "" {
+			buf.WriteByte(' ')
+			buf.WriteString(pos)
+		}
+		fmt.Fprintf(buf, " # score=%.4f pmi=%.4f left=%.4f right=%.4f\n",
+			c.Score, c.PMI, c.LeftEntropy, c.RightEntropy)
+	}
+	return buf.Flush()
+}
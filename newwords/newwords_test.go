@@ -0,0 +1,134 @@
+package newwords
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crossgit/sego"
+)
+
+// 逗号把第二行分成两段，三段连续汉字为"甲乙丙"、"丁甲乙"和"甲乙"，共8个字
+const testCorpus = "甲乙丙\n丁甲乙，甲乙\n"
+
+func newTestDiscoverer(t *testing.T) *Discoverer {
+	t.Helper()
+	d := NewDiscoverer()
+	d.SetMaxLength(2)
+	d.SetMinCount(1)
+	d.SetMinPMI(0)
+	d.SetMinEntropy(0)
+	if err := d.Scan(strings.NewReader(testCorpus)); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func candidateTexts(candidates []Candidate) []string {
+	output := make([]string, len(candidates))
+	for i, c := range candidates {
+		output[i] = c.Text
+	}
+	return output
+}
+
+func TestCandidates(t *testing.T) {
+	d := newTestDiscoverer(t)
+	candidates := d.Candidates(nil)
+
+	// "甲乙"出现3次，"甲"和"乙"各3次：PMI = ln((3/8) / (3/8 * 3/8)) = ln(8/3)
+	// 左邻字为边界、丁、边界，右邻字为丙、边界、边界，左右信息熵都是ln(3)
+	// "乙丙"和"丁甲"只出现1次，PMI同为ln(8/3)，信息熵为0，得分相同时按文本排序
+	if got := candidateTexts(candidates); !reflect.DeepEqual(got, []string{"甲乙", "丁甲", "乙丙"}) {
+		t.Fatalf("Candidates() = %q, want [甲乙 丁甲 乙丙]", got)
+	}
+	c := candidates[0]
+	pmi, h := math.Log(8.0/3), math.Log(3)
+	if c.Count != 3 || !near(c.PMI, pmi) || !near(c.LeftEntropy, h) || !near(c.RightEntropy, h) || !near(c.Score, pmi*h) {
+		t.Errorf("Candidates()[0] = %+v, want count 3, pmi %v, entropy %v, score %v", c, pmi, h, pmi*h)
+	}
+	for _, c := range candidates[1:] {
+		if !near(c.PMI, pmi) || c.LeftEntropy != 0 || c.RightEntropy != 0 || c.Score != 0 {
+			t.Errorf("Candidates() %q = %+v, want pmi %v and zero entropy", c.Text, c, pmi)
+		}
+	}
+}
+
+func TestCandidatesThresholds(t *testing.T) {
+	cases := []struct {
+		name   string
+		change func(d *Discoverer)
+		want   []string
+	}{
+		{"min count", func(d *Discoverer) { d.SetMinCount(2) }, []string{"甲乙"}},
+		{"min entropy", func(d *Discoverer) { d.SetMinEntropy(1) }, []string{"甲乙"}},
+		{"min pmi", func(d *Discoverer) { d.SetMinPMI(1) }, []string{}},
+	}
+	for _, c := range cases {
+		d := newTestDiscoverer(t)
+		c.change(d)
+		if got := candidateTexts(d.Candidates(nil)); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Candidates() = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestCandidatesDictionary(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(file, []byte("甲乙 10 n\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var segmenter sego.Segmenter
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+
+	// 词典中已有的分词被去掉
+	got := candidateTexts(newTestDiscoverer(t).Candidates(segmenter.Dictionary()))
+	if !reflect.DeepEqual(got, []string{"丁甲", "乙丙"}) {
+		t.Errorf("Candidates(dict) = %q, want [丁甲 乙丙]", got)
+	}
+}
+
+func TestWriteCandidates(t *testing.T) {
+	d := newTestDiscoverer(t)
+	d.SetMinEntropy(1)
+	candidates := d.Candidates(nil)
+
+	var output strings.Builder
+	if err := WriteCandidates(&output, candidates, "n"); err != nil {
+		t.Fatal(err)
+	}
+	want := "甲乙 3 n # score=1.0776 pmi=0.9808 left=1.0986 right=1.0986\n"
+	if output.String() != want {
+		t.Errorf("WriteCandidates() = %q, want %q", output.String(), want)
+	}
+
+	output.Reset()
+	if err := WriteCandidates(&output, candidates, ""); err != nil {
+		t.Fatal(err)
+	}
+	if want := "甲乙 3 # score=1.0776 pmi=0.9808 left=1.0986 right=1.0986\n"; output.String() != want {
+		t.Errorf("WriteCandidates() without pos = %q, want %q", output.String(), want)
+	}
+
+	// 输出可以作为词典载入，注释被忽略
+	file := filepath.Join(t.TempDir(), "candidates.txt")
+	if err := os.WriteFile(file, []byte(want), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var segmenter sego.Segmenter
+	if err := segmenter.LoadDictionaryE(file); err != nil {
+		t.Fatal(err)
+	}
+	if token := segmenter.Dictionary().Lookup("甲乙"); token == nil || token.Frequency() != 3 || token.Pos() != "n" {
+		t.Errorf("Lookup(甲乙) = %v, want frequency 3 and pos n", token)
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,494 @@
+package sego
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 分词序列上的模式
+//
+// 模式由空白分隔的元素组成，每个元素匹配若干个连续的分词：
+//	/u          词性为u的分词
+//	@noun       词性属于某一类别的分词，类别名见TagCategory的String()
+//	"电脑"      文本为"电脑"的分词
+//	.           任意一个分词
+//	/u|/p       匹配其中任意一项的分词，各项之间不能有空白
+//	!/x         不匹配该项的分词，可以与"|"组合，比如!/x|/w
+//	~5          任意多个分词，但总长度不超过5个字，尽可能少地匹配
+//	( ... )     捕获组，(?<name> ... )为命名捕获组
+// 除"~"外的元素后面可以加量词"?"、"*"、"+"、"{m}"、"{m,}"或"{m,n}"，量词尽可能多地匹配。
+//
+// 比如SegmentsOutput中"助词后接mOffset个字以内的介词"的规则可以近似地写作
+//	(/u) ~5 (/p)
+// 两者并不完全等价：SegmentsOutput在介词之前遇到另一个助词时从该助词重新开始，
+// 而"~5"可以跨过中间的助词。
+type Pattern struct {
+	expr      string
+	tags      *TagSet
+	elements  []patternElement
+	numGroups int
+	names     []string // 捕获组名称，未命名的为空字符串
+}
+
+// 模式中的一个元素及其量词
+type patternElement struct {
+	atom  *patternAtom  // 匹配单个分词
+	group *patternGroup // 捕获组
+	gap   int           // 大于等于零时为"~"元素，值为最大字数
+
+	min, max int // 重复次数，max为-1时不限
+}
+
+type patternAtom struct {
+	negate bool
+	alts   []patternTest
+}
+
+type patternTest struct {
+	kind     byte // '/'词性，'@'类别，'"'文本，'.'任意
+	value    string
+	category TagCategory
+}
+
+type patternGroup struct {
+	index    int
+	elements []patternElement
+}
+
+// 用DefaultTagSet编译模式
+func CompilePattern(expr string) (*Pattern, error) {
+	return DefaultTagSet.CompilePattern(expr)
+}
+
+// 编译模式，模式中的@类别按该标注集解释
+func (ts *TagSet) CompilePattern(expr string) (*Pattern, error) {
+	p := &Pattern{expr: expr, tags: ts}
+	parser := patternParser{pattern: p, input: []rune(expr)}
+	elements, err := parser.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(parser.input) {
+		return nil, parser.errorf("多余的\")\"")
+	}
+	if len(elements) == 0 {
+		return nil, parser.errorf("空模式")
+	}
+	p.elements = elements
+	return p, nil
+}
+
+// 编译模式，出错时panic，用于定义全局变量
+func MustCompilePattern(expr string) *Pattern {
+	p, err := CompilePattern(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// 返回模式的原始表达式
+func (p *Pattern) String() string {
+	return p.expr
+}
+
+// 模式的一次匹配
+type PatternMatch struct {
+	// 匹配文本及其起止位置，位置与Segment的Start()和End()一致
+	Text  string
+	Start int
+	End   int
+
+	// 匹配的分词，是输入分词结果的子切片
+	Segments []Segment
+
+	// 捕获组，按左括号出现的顺序排列
+	Groups []PatternGroup
+
+	// 匹配左右两侧的上下文
+	Left  string
+	Right string
+}
+
+// 一个捕获组的匹配结果
+type PatternGroup struct {
+	// 捕获组名称，未命名时为空字符串
+	Name string
+
+	// 捕获组是否参与了匹配，可选的捕获组可能没有匹配任何分词
+	Matched bool
+
+	Text     string
+	Start    int
+	End      int
+	Segments []Segment
+}
+
+// 返回名称为name的捕获组
+func (m *PatternMatch) Group(name string) (PatternGroup, bool) {
+	for _, g := range m.Groups {
+		if g.Name == name {
+			return g, true
+		}
+	}
+	return PatternGroup{}, false
+}
+
+// 在分词结果中查找所有不重叠的匹配，lOffset和rOffset为上下文包含的分词数
+func (p *Pattern) FindAll(segs []Segment, lOffset, rOffset int) []PatternMatch {
+	output := make([]PatternMatch, 0)
+	for i := 0; i < len(segs); {
+		caps := make([][2]int, p.numGroups)
+		for k := range caps {
+			caps[k] = [2]int{-1, -1}
+		}
+		end := -1
+		m := matcher{pattern: p, segs: segs, caps: caps}
+		m.matchSequence(p.elements, 0, i, func(j int) bool {
+			end = j
+			return true
+		})
+		if end <= i {
+			i++
+			continue
+		}
+		output = append(output, p.newMatch(segs, i, end, m.caps, lOffset, rOffset))
+		i = end
+	}
+	return output
+}
+
+func (p *Pattern) newMatch(segs []Segment, start, end int, caps [][2]int, lOffset, rOffset int) PatternMatch {
+	match := PatternMatch{
+		Text:     segmentArrayToStr(segs[start:end]),
+		Start:    segs[start].start,
+		End:      segs[end-1].end,
+		Segments: segs[start:end],
+		Groups:   make([]PatternGroup, len(caps)),
+		Left:     segmentArrayToStr(segs[maxInt(0, start-lOffset):start]),
+		Right:    segmentArrayToStr(segs[end:minInt(len(segs), end+rOffset)]),
+	}
+	for k, c := range caps {
+		group := PatternGroup{Name: p.names[k], Start: -1, End: -1}
+		if c[0] >= 0 {
+			group.Matched = true
+			group.Segments = segs[c[0]:c[1]]
+			group.Text = segmentArrayToStr(group.Segments)
+			if c[1] > c[0] {
+				group.Start = segs[c[0]].start
+				group.End = segs[c[1]-1].end
+			}
+		}
+		match.Groups[k] = group
+	}
+	return match
+}
+
+// 回溯匹配器，每个匹配函数在成功时调用后续匹配cont，cont返回true表示整个模式匹配成功
+type matcher struct {
+	pattern *Pattern
+	segs    []Segment
+	caps    [][2]int
+}
+
+func (m *matcher) matchSequence(elements []patternElement, k, pos int, cont func(int) bool) bool {
+	if k == len(elements) {
+		return cont(pos)
+	}
+	el := &elements[k]
+	next := func(j int) bool {
+		return m.matchSequence(elements, k+1, j, cont)
+	}
+	if el.gap >= 0 {
+		return m.matchGap(el.gap, pos, next)
+	}
+	return m.matchRepeat(el, 0, pos, next)
+}
+
+// 按量词重复匹配元素，尽可能多地匹配
+func (m *matcher) matchRepeat(el *patternElement, count, pos int, cont func(int) bool) bool {
+	if el.max < 0 || count < el.max {
+		matched := m.matchOnce(el, pos, func(j int) bool {
+			// 没有消耗分词的重复会导致死循环
+			if j == pos {
+				return false
+			}
+			return m.matchRepeat(el, count+1, j, cont)
+		})
+		if matched {
+			return true
+		}
+	}
+	return count >= el.min && cont(pos)
+}
+
+func (m *matcher) matchOnce(el *patternElement, pos int, cont func(int) bool) bool {
+	if el.group != nil {
+		// 记录捕获组的位置，后续匹配失败时恢复
+		index := el.group.index
+		return m.matchSequence(el.group.elements, 0, pos, func(j int) bool {
+			saved := m.caps[index]
+			m.caps[index] = [2]int{pos, j}
+			if cont(j) {
+				return true
+			}
+			m.caps[index] = saved
+			return false
+		})
+	}
+	if pos < len(m.segs) && m.matchAtom(el.atom, &m.segs[pos]) {
+		return cont(pos + 1)
+	}
+	return false
+}
+
+// 匹配总长度不超过maxLength个字的任意分词，尽可能少地匹配
+func (m *matcher) matchGap(maxLength, pos int, cont func(int) bool) bool {
+	for j := pos; j <= len(m.segs); j++ {
+		if j > pos && m.segs[j-1].end-m.segs[pos].start > maxLength {
+			return false
+		}
+		if cont(j) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *matcher) matchAtom(atom *patternAtom, s *Segment) bool {
+	matched := false
+	for _, test := range atom.alts {
+		switch test.kind {
+		case '/':
+			matched = s.Pos() == test.value
+		case '@':
+			matched = m.pattern.tags.Is(s.Pos(), test.category)
+		case '"':
+			matched = s.token.Text() == test.value
+		case '.':
+			matched = true
+		}
+		if matched {
+			break
+		}
+	}
+	return matched != atom.negate
+}
+
+// 模式解析器
+type patternParser struct {
+	pattern *Pattern
+	input   []rune
+	pos     int
+}
+
+func (parser *patternParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("模式 \"%s\" 第%d个字符：%s",
+		parser.pattern.expr, parser.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (parser *patternParser) skipSpaces() {
+	for parser.pos < len(parser.input) && unicode.IsSpace(parser.input[parser.pos]) {
+		parser.pos++
+	}
+}
+
+func (parser *patternParser) peek() rune {
+	if parser.pos < len(parser.input) {
+		return parser.input[parser.pos]
+	}
+	return 0
+}
+
+// 解析元素序列，直到输入结束或遇到")"
+func (parser *patternParser) parseSequence() ([]patternElement, error) {
+	elements := make([]patternElement, 0)
+	for {
+		parser.skipSpaces()
+		c := parser.peek()
+		if c == 0 || c == ')' {
+			return elements, nil
+		}
+
+		var el patternElement
+		el.gap = -1
+		switch {
+		case c == '(':
+			group, err := parser.parseGroup()
+			if err != nil {
+				return nil, err
+			}
+			el.group = group
+		case c == '~':
+			parser.pos++
+			n, ok := parser.parseInt()
+			if !ok {
+				return nil, parser.errorf("\"~\"后需要最大字数")
+			}
+			el.gap = n
+			elements = append(elements, el)
+			continue
+		default:
+			atom, err := parser.parseAtom()
+			if err != nil {
+				return nil, err
+			}
+			el.atom = atom
+		}
+		if err := parser.parseQuantifier(&el); err != nil {
+			return nil, err
+		}
+		elements = append(elements, el)
+	}
+}
+
+func (parser *patternParser) parseGroup() (*patternGroup, error) {
+	parser.pos++
+	name := ""
+	if strings.HasPrefix(string(parser.input[parser.pos:]), "?<") {
+		parser.pos += 2
+		name = parser.parseName()
+		if name == "" || parser.peek() != '>' {
+			return nil, parser.errorf("无效的捕获组名称")
+		}
+		parser.pos++
+	}
+
+	group := &patternGroup{index: parser.pattern.numGroups}
+	parser.pattern.numGroups++
+	parser.pattern.names = append(parser.pattern.names, name)
+
+	elements, err := parser.parseSequence()
+	if err != nil {
+		return nil, err
+	}
+	if parser.peek() != ')' {
+		return nil, parser.errorf("缺少\")\"")
+	}
+	parser.pos++
+	group.elements = elements
+	return group, nil
+}
+
+func (parser *patternParser) parseAtom() (*patternAtom, error) {
+	atom := &patternAtom{}
+	if parser.peek() == '!' {
+		atom.negate = true
+		parser.pos++
+	}
+	for {
+		test, err := parser.parseTest()
+		if err != nil {
+			return nil, err
+		}
+		atom.alts = append(atom.alts, test)
+		if parser.peek() != '|' {
+			return atom, nil
+		}
+		parser.pos++
+	}
+}
+
+func (parser *patternParser) parseTest() (patternTest, error) {
+	c := parser.peek()
+	switch c {
+	case '.':
+		parser.pos++
+		return patternTest{kind: '.'}, nil
+	case '"':
+		parser.pos++
+		start := parser.pos
+		for parser.pos < len(parser.input) && parser.input[parser.pos] != '"' {
+			parser.pos++
+		}
+		if parser.pos == len(parser.input) {
+			return patternTest{}, parser.errorf("引号没有结束")
+		}
+		value := string(parser.input[start:parser.pos])
+		parser.pos++
+		return patternTest{kind: '"', value: strings.ToLower(value)}, nil
+	case '/', '@':
+		parser.pos++
+		name := parser.parseName()
+		if name == "" {
+			return patternTest{}, parser.errorf("\"%c\"后需要名称", c)
+		}
+		test := patternTest{kind: byte(c), value: name}
+		if c == '@' {
+			category, ok := parseTagCategory(name)
+			if !ok {
+				return patternTest{}, parser.errorf("未知的词性类别 \"%s\"", name)
+			}
+			test.category = category
+		}
+		return test, nil
+	}
+	return patternTest{}, parser.errorf("无法识别的元素")
+}
+
+// 读入由字母、数字和下划线组成的名称
+func (parser *patternParser) parseName() string {
+	start := parser.pos
+	for parser.pos < len(parser.input) {
+		r := parser.input[parser.pos]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			break
+		}
+		parser.pos++
+	}
+	return string(parser.input[start:parser.pos])
+}
+
+func (parser *patternParser) parseInt() (int, bool) {
+	start := parser.pos
+	for parser.pos < len(parser.input) && parser.input[parser.pos] >= '0' && parser.input[parser.pos] <= '9' {
+		parser.pos++
+	}
+	n, err := strconv.Atoi(string(parser.input[start:parser.pos]))
+	return n, err == nil
+}
+
+func (parser *patternParser) parseQuantifier(el *patternElement) error {
+	el.min, el.max = 1, 1
+	switch parser.peek() {
+	case '?':
+		el.min, el.max = 0, 1
+	case '*':
+		el.min, el.max = 0, -1
+	case '+':
+		el.min, el.max = 1, -1
+	case '{':
+		parser.pos++
+		min, ok := parser.parseInt()
+		if !ok {
+			return parser.errorf("量词需要最小次数")
+		}
+		el.min, el.max = min, min
+		if parser.peek() == ',' {
+			parser.pos++
+			el.max = -1
+			if max, ok := parser.parseInt(); ok {
+				el.max = max
+			}
+		}
+		if parser.peek() != '}' || (el.max >= 0 && el.max < el.min) {
+			return parser.errorf("无效的量词")
+		}
+	default:
+		return nil
+	}
+	parser.pos++
+	return nil
+}
+
+// 由名称查找词性类别
+func parseTagCategory(name string) (TagCategory, bool) {
+	for c, n := range tagCategoryNames {
+		if n == name {
+			return TagCategory(c), true
+		}
+	}
+	return 0, false
+}
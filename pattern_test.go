@@ -0,0 +1,109 @@
+package sego
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// 把匹配格式化为"起-止 文本"，后接各捕获组和左右上下文
+func formatMatch(m PatternMatch) string {
+	s := fmt.Sprintf("%d-%d %s", m.Start, m.End, m.Text)
+	for _, g := range m.Groups {
+		if g.Matched {
+			s += fmt.Sprintf(" (%s=%s@%d-%d)", g.Name, g.Text, g.Start, g.End)
+		} else {
+			s += fmt.Sprintf(" (%s-)", g.Name)
+		}
+	}
+	if m.Left != "" || m.Right != "" {
+		s += fmt.Sprintf(" <%s|%s>", m.Left, m.Right)
+	}
+	return s
+}
+
+func TestPatternFindAll(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"我 100 r",
+		"的 100 u",
+		"电脑 100 n",
+		"放 100 v",
+		"在 100 p",
+		"桌子 100 n",
+		"上 100 f",
+	))
+	// 我/r 的/u 电脑/n 放/v 在/p 桌子/n 上/f
+	segs := seg.Segment([]byte("我的电脑放在桌子上"))
+
+	cases := []struct {
+		expr             string
+		lOffset, rOffset int
+		want             []string
+	}{
+		// 文本、词性和类别
+		{`"电脑"`, 0, 0, []string{"2-4 电脑"}},
+		{`/n`, 0, 0, []string{"2-4 电脑", "6-8 桌子"}},
+		{`@noun`, 0, 0, []string{"2-4 电脑", "6-8 桌子"}},
+		{`. "电脑"`, 0, 0, []string{"1-4 的电脑"}},
+		{`/v|/p /n`, 0, 0, []string{"5-8 在桌子"}},
+		{`!/n|/u /n`, 0, 0, []string{"5-8 在桌子"}},
+
+		// "~"间隔按字数计算，尽可能少地匹配
+		{`/u ~3 /p`, 0, 0, []string{"1-6 的电脑放在"}},
+		{`/u ~2 /p`, 0, 0, []string{}},
+		{`/n ~0 /v`, 0, 0, []string{"2-5 电脑放"}},
+		{`/n ~10 /n`, 0, 0, []string{"2-8 电脑放在桌子"}},
+
+		// 量词
+		{`/r /u? /n`, 0, 0, []string{"0-4 我的电脑"}},
+		{`"电脑" /u? /v`, 0, 0, []string{"2-5 电脑放"}},
+		{`!/p+`, 0, 0, []string{"0-5 我的电脑放", "6-9 桌子上"}},
+		{`!/p*`, 0, 0, []string{"0-5 我的电脑放", "6-9 桌子上"}},
+		{`/n|/v{2}`, 0, 0, []string{"2-5 电脑放"}},
+		{`/n|/v{2,}`, 0, 0, []string{"2-5 电脑放"}},
+		{`.{2,3}`, 0, 0, []string{"0-4 我的电脑", "4-8 放在桌子"}},
+
+		// 捕获组
+		{`(?<owner>/r) /u (/n)`, 0, 0, []string{"0-4 我的电脑 (owner=我@0-1) (=电脑@2-4)"}},
+		{`(/r)? /v`, 0, 0, []string{"4-5 放 (-)"}},
+		{`(/u) ~5 (?<prep>/p)`, 0, 0, []string{"1-6 的电脑放在 (=的@1-2) (prep=在@5-6)"}},
+
+		// 上下文
+		{`/p`, 2, 1, []string{"5-6 在 <电脑放|桌子>"}},
+		{`/r`, 2, 2, []string{"0-1 我 <|的电脑>"}},
+		{`/f`, 1, 3, []string{"8-9 上 <桌子|>"}},
+	}
+	for _, c := range cases {
+		p, err := CompilePattern(c.expr)
+		if err != nil {
+			t.Errorf("CompilePattern(%q) error: %s", c.expr, err)
+			continue
+		}
+		got := make([]string, 0)
+		for _, m := range p.FindAll(segs, c.lOffset, c.rOffset) {
+			got = append(got, formatMatch(m))
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%q.FindAll() = %q, want %q", c.expr, got, c.want)
+		}
+	}
+
+	m := MustCompilePattern(`(?<owner>/r) /u`).FindAll(segs, 0, 0)[0]
+	if g, ok := m.Group("owner"); !ok || g.Text != "我" {
+		t.Errorf("Group(owner) = %v %v, want 我", g, ok)
+	}
+	if _, ok := m.Group("missing"); ok {
+		t.Error("Group(missing) found")
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	for _, expr := range []string{
+		"", "(/n", "/n)", "~", "~/n", "@foo", `"abc`, "/", "/n{3,1}", "/n{", "(?<>/n)", "&",
+	} {
+		if _, err := CompilePattern(expr); err == nil {
+			t.Errorf("CompilePattern(%q) returned nil error", expr)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 拼音词典
+//
+// 词典包括单字的读音和词语的读音两部分。多音字的读音由其所在的分词决定：
+// 分词在词语读音表中时使用词语的读音，比如"重庆"读作"chong qing"而"重要"读作
+// "zhong yao"；否则逐字取每个字的第一个读音。
+type PinyinDictionary struct {
+	chars map[rune][]string
+	words map[string][]string
+}
+
+// 新建一个空的拼音词典
+func NewPinyinDictionary() *PinyinDictionary {
+	return &PinyinDictionary{
+		chars: make(map[rune][]string),
+		words: make(map[string][]string),
+	}
+}
+
+// 添加单字的读音，排在前面的读音为默认读音
+func (p *PinyinDictionary) AddChar(char rune, readings ...string) {
+	for _, reading := range readings {
+		reading = normalizePinyin(reading)
+		if reading != "" && !containsString(p.chars[char], reading) {
+			p.chars[char] = append(p.chars[char], reading)
+		}
+	}
+}
+
+// 添加词语的读音，每个字一个音节
+func (p *PinyinDictionary) AddWord(word string, syllables ...string) {
+	normalized := make([]string, len(syllables))
+	for i, syllable := range syllables {
+		normalized[i] = normalizePinyin(syllable)
+	}
+	p.words[strings.ToLower(word)] = normalized
+}
+
+// 从reader中载入拼音词典，每行一个单字或词语，格式为
+//	重 zhong,chong
+//	重庆 chong qing
+// 单字的多个读音用","分隔，词语的音节用空白分隔。音节中的声调数字会被去掉。
+func (p *PinyinDictionary) Load(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			// 无效行
+			continue
+		}
+		if utf8.RuneCountInString(fields[0]) == 1 {
+			char, _ := utf8.DecodeRuneInString(fields[0])
+			for _, field := range fields[1:] {
+				p.AddChar(char, strings.Split(field, ",")...)
+			}
+		} else {
+			p.AddWord(fields[0], fields[1:]...)
+		}
+	}
+	return scanner.Err()
+}
+
+// 从文件中载入拼音词典，多个文件名用","分隔
+func (p *PinyinDictionary) LoadFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		pinyinFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = p.Load(pinyinFile)
+		pinyinFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 返回单字的所有读音
+func (p *PinyinDictionary) Readings(char rune) []string {
+	return p.chars[char]
+}
+
+// 返回分词的拼音，每个汉字一个音节，没有读音的连续字元（比如英文和数字）原样作为一个音节
+func (p *PinyinDictionary) Pinyin(word string) []string {
+	word = strings.ToLower(word)
+	if syllables, ok := p.words[word]; ok {
+		return syllables
+	}
+
+	output := make([]string, 0, utf8.RuneCountInString(word))
+	other := 0
+	for i, r := range word {
+		readings := p.chars[r]
+		if len(readings) == 0 && !unicode.Is(unicode.Han, r) {
+			continue
+		}
+		if other < i {
+			output = append(output, word[other:i])
+		}
+		if len(readings) > 0 {
+			output = append(output, readings[0])
+		} else {
+			output = append(output, string(r))
+		}
+		other = i + utf8.RuneLen(r)
+	}
+	if other < len(word) {
+		output = append(output, word[other:])
+	}
+	return output
+}
+
+// 去掉拼音中的声调数字和空白，转为小写，"ü"写作"v"
+func normalizePinyin(syllable string) string {
+	syllable = strings.ToLower(strings.TrimSpace(syllable))
+	syllable = strings.TrimRight(syllable, "012345")
+	return strings.Replace(syllable, "ü", "v", -1)
+}
+
+// 拼音的全拼和首字母，作为Dictionary.LookupPinyin的索引
+func pinyinKeys(syllables []string) (full, initials string) {
+	for _, syllable := range syllables {
+		full += syllable
+		if syllable != "" {
+			initials += syllable[:1]
+		}
+	}
+	return
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 为不在词典中的分词标注拼音
+//
+// 未登录的词语会被切分成单字，此时连续的单字如果组成拼音词典中的词语，
+// 则按词语的读音标注，比如"重/x 庆/x"仍然读作"chong qing"。
+func (p *PinyinDictionary) annotateSegments(segments []Segment) {
+	for i := 0; i < len(segments); i++ {
+		if segments[i].token.pinyin != nil {
+			continue
+		}
+
+		// 寻找以当前分词开头、在拼音词典中的最长词语
+		matched, text := 0, ""
+		for j := i; j < len(segments) && segments[j].token.pinyin == nil; j++ {
+			text += segments[j].token.Text()
+			syllables, ok := p.words[strings.ToLower(text)]
+			if ok && len(syllables) == utf8.RuneCountInString(text) {
+				matched = j - i + 1
+			}
+		}
+		if matched < 2 {
+			segments[i].pinyin = p.Pinyin(segments[i].token.Text())
+			continue
+		}
+
+		text = segmentArrayToStr(segments[i : i+matched])
+		syllables := p.words[strings.ToLower(text)]
+		for j := i; j < i+matched; j++ {
+			n := utf8.RuneCountInString(segments[j].token.Text())
+			segments[j].pinyin, syllables = syllables[:n], syllables[n:]
+		}
+		i += matched - 1
+	}
+}
+
+// 设置分词器使用的拼音词典，设置后词典中的分词和Segment的结果都带有拼音标注
+func (seg *Segmenter) SetPinyinDictionary(p *PinyinDictionary) {
+	seg.pinyin = p
+	if seg.dict != nil {
+		seg.dict.annotatePinyin(p)
+	}
+}
+
+// 为词典中所有分词标注拼音并建立拼音索引
+func (dict *Dictionary) annotatePinyin(p *PinyinDictionary) {
+	dict.pinyinIndex = make(map[string][]int)
+	for i := range dict.tokens {
+		token := &dict.tokens[i]
+		token.pinyin = p.Pinyin(token.Text())
+		full, initials := pinyinKeys(token.pinyin)
+		dict.pinyinIndex[full] = append(dict.pinyinIndex[full], i)
+		if initials != full {
+			dict.pinyinIndex[initials] = append(dict.pinyinIndex[initials], i)
+		}
+	}
+}
+
+// 按全拼或拼音首字母查找词典中的分词，比如"chongqing"或"cq"都可以找到"重庆"。
+// 查询中的空白和声调数字会被忽略，需要先通过Segmenter.SetPinyinDictionary标注拼音。
+func (dict *Dictionary) LookupPinyin(pinyin string) []*Token {
+	key := ""
+	for _, syllable := range strings.Fields(pinyin) {
+		key += strings.Map(func(r rune) rune {
+			if r >= '0' && r <= '5' {
+				return -1
+			}
+			return r
+		}, normalizePinyin(syllable))
+	}
+
+	output := make([]*Token, 0, len(dict.pinyinIndex[key]))
+	for _, i := range dict.pinyinIndex[key] {
+		output = append(output, &dict.tokens[i])
+	}
+	return output
+}
@@ -0,0 +1,273 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// 基于隐马尔可夫模型（HMM）的词性标注器
+//
+// 词典中的词性与上下文无关，同一分词总是得到相同的词性。标注器在分词结果上
+// 运行Viterbi算法，根据相邻词性的转移概率从分词的候选词性中选出最可能的一个，
+// 并为未登录的"x"分词推测词性。模型参数从标注好的语料中训练得到，语料格式为
+// 每行一个句子，分词之间用空白分隔，每个分词写作"分词文本/词性"，比如
+//	迈向/v 充满/v 希望/n 的/u 新/a 世纪/n
+type PosTagger struct {
+	numSentences int
+	startCount   map[string]int            // 句首词性出现次数
+	tagCount     map[string]int            // 各词性出现次数
+	transCount   map[string]map[string]int // 前一词性 -> 后一词性 -> 次数
+	emitCount    map[string]map[string]int // 分词文本 -> 词性 -> 次数
+
+	// 以下为训练后计算的对数概率
+	tags      []string
+	logStart  map[string]float64
+	logTrans  map[string]map[string]float64
+	logUnseen map[string]float64 // 各词性生成语料中未出现的分词的概率
+}
+
+// 新建一个未经训练的标注器
+func NewPosTagger() *PosTagger {
+	return &PosTagger{
+		startCount: make(map[string]int),
+		tagCount:   make(map[string]int),
+		transCount: make(map[string]map[string]int),
+		emitCount:  make(map[string]map[string]int),
+	}
+}
+
+// 从文件中训练标注器，多个文件名用","分隔
+func (tagger *PosTagger) TrainFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		corpusFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = tagger.Train(corpusFile)
+		corpusFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 从标注好的语料中训练标注器，可以多次调用以累加语料
+func (tagger *PosTagger) Train(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		prev := ""
+		for _, field := range strings.Fields(scanner.Text()) {
+			word, pos, ok := parseTaggedWord(field)
+			if !ok {
+				continue
+			}
+			if prev == "" {
+				tagger.numSentences++
+				tagger.startCount[pos]++
+			} else {
+				if tagger.transCount[prev] == nil {
+					tagger.transCount[prev] = make(map[string]int)
+				}
+				tagger.transCount[prev][pos]++
+			}
+			if tagger.emitCount[word] == nil {
+				tagger.emitCount[word] = make(map[string]int)
+			}
+			tagger.emitCount[word][pos]++
+			tagger.tagCount[pos]++
+			prev = pos
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	tagger.estimate()
+	return nil
+}
+
+// 解析语料中的"分词文本/词性"，兼容人民日报语料中"[中国/ns 政府/n]nt"形式的复合词
+func parseTaggedWord(field string) (word, pos string, ok bool) {
+	field = strings.TrimPrefix(field, "[")
+	i := strings.LastIndex(field, "/")
+	if i <= 0 || i == len(field)-1 {
+		return "", "", false
+	}
+	word, pos = field[:i], field[i+1:]
+	if j := strings.Index(pos, "]"); j >= 0 {
+		pos = pos[:j]
+	}
+	return strings.ToLower(word), pos, pos != ""
+}
+
+// 由计数估计对数概率，转移概率和句首概率使用加一平滑
+func (tagger *PosTagger) estimate() {
+	tagger.tags = tagger.tags[:0]
+	for tag := range tagger.tagCount {
+		tagger.tags = append(tagger.tags, tag)
+	}
+	sort.Strings(tagger.tags)
+	numTags := float64(len(tagger.tags))
+
+	tagger.logStart = make(map[string]float64)
+	tagger.logTrans = make(map[string]map[string]float64)
+	for _, a := range tagger.tags {
+		tagger.logStart[a] = math.Log(
+			float64(tagger.startCount[a]+1) / (float64(tagger.numSentences) + numTags))
+		tagger.logTrans[a] = make(map[string]float64)
+		for _, b := range tagger.tags {
+			tagger.logTrans[a][b] = math.Log(
+				float64(tagger.transCount[a][b]+1) / (float64(tagger.tagCount[a]) + numTags))
+		}
+	}
+
+	// 用只出现一次的分词估计各词性生成未见分词的概率
+	hapax := make(map[string]int)
+	for _, counts := range tagger.emitCount {
+		total := 0
+		for _, count := range counts {
+			total += count
+		}
+		if total == 1 {
+			for tag := range counts {
+				hapax[tag]++
+			}
+		}
+	}
+	tagger.logUnseen = make(map[string]float64)
+	for _, tag := range tagger.tags {
+		if hapax[tag] > 0 {
+			tagger.logUnseen[tag] = math.Log(float64(hapax[tag]) / float64(tagger.tagCount[tag]))
+		}
+	}
+}
+
+// 标注器的一个候选词性及其生成概率
+type posCandidate struct {
+	pos     string
+	logEmit float64
+}
+
+// 返回分词的候选词性
+//
+//	1. 空白和标点，保持原有词性
+//	2. 语料中出现过的分词，使用语料中的词性和频率
+//	3. 词典中的分词，使用词典中的词性，生成概率近似为p(词性|分词)*p(未见分词|词性)
+//	4. 未登录的"x"分词，候选为所有开放词性（语料中有只出现一次的分词的词性）
+//	5. 其它非词典分词（比如按SymbolPolicy标注的标点），保持原有词性
+func (tagger *PosTagger) candidates(token *Token) []posCandidate {
+	if symbolKind(token) != symbolNone {
+		return []posCandidate{{token.pos, 0}}
+	}
+
+	if counts, ok := tagger.emitCount[textSliceToString(token.text)]; ok {
+		// 按tags的顺序输出，使Viterbi算法在概率相同时的选择是确定的
+		output := make([]posCandidate, 0, len(counts))
+		for _, pos := range tagger.tags {
+			if count, ok := counts[pos]; ok {
+				output = append(output, posCandidate{
+					pos, math.Log(float64(count) / float64(tagger.tagCount[pos]))})
+			}
+		}
+		return output
+	}
+
+	if len(token.tags) > 0 {
+		output := make([]posCandidate, 0, len(token.tags))
+		for _, tag := range token.tags {
+			logEmit := math.Log(float64(tag.Frequency) / float64(token.frequency))
+			if logUnseen, ok := tagger.logUnseen[tag.Pos]; ok {
+				logEmit += logUnseen
+			} else {
+				logEmit += math.Log(1 / float64(tagger.tagCount[tag.Pos]+1))
+			}
+			output = append(output, posCandidate{tag.Pos, logEmit})
+		}
+		return output
+	}
+
+	if token.pos == "x" && len(tagger.logUnseen) > 0 {
+		output := make([]posCandidate, 0, len(tagger.logUnseen))
+		for _, pos := range tagger.tags {
+			if logUnseen, ok := tagger.logUnseen[pos]; ok {
+				output = append(output, posCandidate{pos, logUnseen})
+			}
+		}
+		return output
+	}
+
+	return []posCandidate{{token.pos, 0}}
+}
+
+// 返回从词性a转移到词性b的对数概率，语料中没有的词性按均匀分布处理
+func (tagger *PosTagger) transition(a, b string) float64 {
+	if trans, ok := tagger.logTrans[a]; ok {
+		if logProb, ok := trans[b]; ok {
+			return logProb
+		}
+	}
+	return math.Log(1 / float64(len(tagger.tags)+1))
+}
+
+// 返回词性作为句首的对数概率
+func (tagger *PosTagger) start(pos string) float64 {
+	if logProb, ok := tagger.logStart[pos]; ok {
+		return logProb
+	}
+	return math.Log(1 / float64(tagger.numSentences+len(tagger.tags)+1))
+}
+
+// 对分词结果进行词性标注，标注结果可以通过Segment.Pos()得到
+func (tagger *PosTagger) Tag(segments []Segment) {
+	if len(segments) == 0 || len(tagger.tags) == 0 {
+		return
+	}
+
+	// Viterbi算法，scores[i][k]为第i个分词取第k个候选词性时的最大对数概率，
+	// backs[i][k]为取得该最大值时前一个分词的候选词性序号
+	candidates := make([][]posCandidate, len(segments))
+	scores := make([][]float64, len(segments))
+	backs := make([][]int, len(segments))
+	for i := range segments {
+		candidates[i] = tagger.candidates(segments[i].token)
+		scores[i] = make([]float64, len(candidates[i]))
+		backs[i] = make([]int, len(candidates[i]))
+		for k, c := range candidates[i] {
+			if i == 0 {
+				scores[i][k] = tagger.start(c.pos) + c.logEmit
+				continue
+			}
+			scores[i][k] = math.Inf(-1)
+			for l, p := range candidates[i-1] {
+				score := scores[i-1][l] + tagger.transition(p.pos, c.pos) + c.logEmit
+				if score > scores[i][k] {
+					scores[i][k] = score
+					backs[i][k] = l
+				}
+			}
+		}
+	}
+
+	// 从后向前回溯最优路径
+	best := 0
+	last := len(segments) - 1
+	for k := range scores[last] {
+		if scores[last][k] > scores[last][best] {
+			best = k
+		}
+	}
+	for i := last; i >= 0; i-- {
+		segments[i].pos = candidates[i][best].pos
+		best = backs[i][best]
+	}
+}
+
+// 设置分词器使用的词性标注器，为nil时Segment直接使用词典中的词性
+func (seg *Segmenter) SetPosTagger(tagger *PosTagger) {
+	seg.posTagger = tagger
+}
@@ -0,0 +1,104 @@
+package sego
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPosTaggerKeepsSymbols(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"中国 100 ns",
+		"人民 100 n",
+		"发展 80 v 20 vn",
+	))
+
+	tagger := NewPosTagger()
+	err := tagger.Train(strings.NewReader("中国/ns 人民/n 发展/v 经济/n\n人民/n 热爱/v 和平/n\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seg.SetPosTagger(tagger)
+
+	segs := seg.Segment([]byte("中国 人民，发展"))
+	got := taggedWords(segs)
+	want := []string{"中国/ns", " /x", "人民/n", "，/x", "发展/v"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Segment() = %q, want %q", got, want)
+	}
+	if nouns := SegmentsOutputArray(segs); !reflect.DeepEqual(nouns, []string{"人民"}) {
+		t.Errorf("SegmentsOutputArray() = %q, want [人民]", nouns)
+	}
+}
+
+// 训练用的小语料，"发展"在名词后为vn，在"要"后为v
+const testPosCorpus = `经济/n 发展/vn 很/d 快/a
+社会/n 发展/vn 很/d 好/a
+我们/r 要/v 发展/v 农业/n
+他们/r 要/v 发展/v 工业/n
+`
+
+func newTestTaggedSegmenter(t *testing.T) *Segmenter {
+	t.Helper()
+	seg := new(Segmenter)
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"经济 100 n",
+		"社会 100 n",
+		"发展 80 v 20 vn",
+		"很 100 d",
+		"快 50 a",
+		"我们 100 r",
+		"要 100 v",
+	))
+	tagger := NewPosTagger()
+	if err := tagger.Train(strings.NewReader(testPosCorpus)); err != nil {
+		t.Fatal(err)
+	}
+	seg.SetPosTagger(tagger)
+	return seg
+}
+
+func taggedWords(segs []Segment) []string {
+	output := make([]string, len(segs))
+	for i := range segs {
+		output[i] = segs[i].Token().Text() + "/" + segs[i].Pos()
+	}
+	return output
+}
+
+func TestPosTaggerContext(t *testing.T) {
+	seg := newTestTaggedSegmenter(t)
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"经济发展很快", []string{"经济/n", "发展/vn", "很/d", "快/a"}},
+		{"我们要发展经济", []string{"我们/r", "要/v", "发展/v", "经济/n"}},
+	}
+	for _, c := range cases {
+		// 多次标注结果相同
+		for i := 0; i < 10; i++ {
+			if got := taggedWords(seg.Segment([]byte(c.text))); !reflect.DeepEqual(got, c.want) {
+				t.Errorf("Segment(%q) = %q, want %q", c.text, got, c.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPosTaggerUnknownWord(t *testing.T) {
+	seg := newTestTaggedSegmenter(t)
+
+	// 未登录的"鑫"在"发展/v"之后，推测为开放词性n
+	want := []string{"我们/r", "要/v", "发展/v", "鑫/n"}
+	if got := taggedWords(seg.Segment([]byte("我们要发展鑫"))); !reflect.DeepEqual(got, want) {
+		t.Errorf("Segment() = %q, want %q", got, want)
+	}
+
+	// 没有标注器时仍为"x"
+	seg.SetPosTagger(nil)
+	if got := taggedWords(seg.Segment([]byte("鑫"))); !reflect.DeepEqual(got, []string{"鑫/x"}) {
+		t.Errorf("Segment() without tagger = %q, want [鑫/x]", got)
+	}
+}
@@ -10,6 +10,12 @@ type Segment struct {
 
 	// 分词信息
 	token *Token
+
+	// 词性标注器在上下文中选出的词性，为空时使用分词的词典词性
+	pos string
+
+	// 不在词典中的分词的拼音，词典分词的拼音见Token.Pinyin
+	pinyin []string
 }
 
 // 返回分词在文本中的起始字节位置
@@ -26,3 +32,19 @@ func (s *Segment) End() int {
 func (s *Segment) Token() *Token {
 	return s.token
 }
+
+// 返回分词在上下文中的词性，没有经过词性标注时返回词典中的词性
+func (s *Segment) Pos() string {
+	if s.pos != "" {
+		return s.pos
+	}
+	return s.token.pos
+}
+
+// 返回分词的拼音，分词器没有设置拼音词典时返回nil
+func (s *Segment) Pinyin() []string {
+	if s.pinyin != nil {
+		return s.pinyin
+	}
+	return s.token.pinyin
+}
@@ -14,6 +14,24 @@ const (
 // 分词器结构体
 type Segmenter struct {
 	dict *Dictionary
+
+	// 空白与标点的处理策略，见SymbolPolicy的注释
+	symbolPolicy SymbolPolicy
+
+	// 载入词典时校验词性所用的标注集，为nil时不校验
+	tagSet *TagSet
+
+	// 词性标注器，为nil时不做上下文相关的词性标注
+	posTagger *PosTagger
+
+	// 停用词表，见LoadStopWords
+	stopWords map[string]bool
+
+	// 拼音词典，为nil时不标注拼音
+	pinyin *PinyinDictionary
+
+	// 未登录词收集器，为nil时不收集
+	unknownWords *UnknownWordCollector
 }
 
 // 该结构体用于记录Viterbi算法中某字元处的向前分词跳转信息
@@ -44,7 +62,17 @@ func (seg *Segmenter) Segment(bytes []byte) []Segment {
 	// 划分字元
 	text := splitTextToWords(bytes)
 	// log.Println("internalSegment:", textSliceToString(text))
-	return seg.cutJump(text, false)
+	segments := seg.applySymbolPolicy(seg.cutJump(text, false))
+	if seg.unknownWords != nil {
+		seg.unknownWords.collect(seg.TagSet(), bytes, segments)
+	}
+	if seg.posTagger != nil {
+		seg.posTagger.Tag(segments)
+	}
+	if seg.pinyin != nil {
+		seg.pinyin.annotateSegments(segments)
+	}
+	return segments
 }
 
 // CutAll 逐字全切结构
@@ -0,0 +1,197 @@
+// Bleve全文检索的sego分词器和分析器
+//
+// 导入本包后在Bleve中注册了名为"sego"的分词器，以及"sego"（精确模式）和
+// "sego_search"（搜索模式）两个分析器。使用前需要用SetSegmenter设置已载入词典的
+// 分词器，或者在索引映射的自定义分词器中用"dictionary"指定词典文件：
+//	"tokenizers": {
+//		"sego_custom": {"type": "sego", "dictionary": "dictionary.txt", "search_mode": true}
+//	}
+package segobleve
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+	"github.com/blevesearch/bleve/v2/registry"
+
+	"github.com/crossgit/sego"
+)
+
+const (
+	// 分词器的注册名
+	Name = "sego"
+
+	// 精确模式和搜索模式分析器的注册名
+	AnalyzerName       = "sego"
+	SearchAnalyzerName = "sego_search"
+)
+
+var (
+	defaultSegmenter *sego.Segmenter
+
+	// 按词典文件缓存的分词器，同一组词典只载入一次
+	segmenters     = make(map[string]*sego.Segmenter)
+	segmentersLock sync.Mutex
+)
+
+// 设置没有指定"dictionary"时使用的分词器，需在创建索引之前调用
+func SetSegmenter(segmenter *sego.Segmenter) {
+	segmentersLock.Lock()
+	defer segmentersLock.Unlock()
+	defaultSegmenter = segmenter
+}
+
+// 产生analysis.Token的分词器
+//
+// Token的Start和End为字节位置。精确模式下每个分词的位置依次加一；搜索模式下
+// 分词的子分词与分词本身位置相同，这样短语查询在两种模式下都能匹配。
+// 标点和空白不输出，但不占用位置。
+type Tokenizer struct {
+	segmenter  *sego.Segmenter
+	searchMode bool
+}
+
+// 新建使用segmenter分词的分词器
+func NewTokenizer(segmenter *sego.Segmenter, searchMode bool) *Tokenizer {
+	return &Tokenizer{segmenter: segmenter, searchMode: searchMode}
+}
+
+// 实现analysis.Tokenizer
+func (t *Tokenizer) Tokenize(input []byte) analysis.TokenStream {
+	segs := t.segmenter.Segment(input)
+	offsets := runeToByteOffsets(input)
+	output := make(analysis.TokenStream, 0, len(segs))
+
+	position := 0
+	for i := range segs {
+		var terms []sego.SearchTerm
+		if t.searchMode {
+			terms = sego.SearchTerms(segs[i : i+1])
+		} else {
+			terms = []sego.SearchTerm{{
+				Text:  segs[i].Token().Text(),
+				Start: segs[i].Start(),
+				End:   segs[i].End(),
+			}}
+		}
+
+		first := true
+		for _, term := range terms {
+			tokenType, ok := termType(term.Text)
+			if !ok {
+				continue
+			}
+			if first {
+				position++
+				first = false
+			}
+			output = append(output, &analysis.Token{
+				Start:    offsets[term.Start],
+				End:      offsets[term.End],
+				Term:     []byte(term.Text),
+				Position: position,
+				Type:     tokenType,
+			})
+		}
+	}
+	return output
+}
+
+// 返回词条的类型，标点和空白返回false
+func termType(term string) (analysis.TokenType, bool) {
+	numeric, letter := true, false
+	tokenType := analysis.AlphaNumeric
+	for _, r := range term {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			tokenType = analysis.Ideographic
+			letter = true
+			numeric = false
+		case unicode.IsLetter(r):
+			letter = true
+			numeric = false
+		case unicode.IsNumber(r):
+			letter = true
+		default:
+			numeric = false
+		}
+	}
+	if !letter {
+		return 0, false
+	}
+	if numeric {
+		return analysis.Numeric, true
+	}
+	return tokenType, true
+}
+
+// 分词的位置以字计算，返回每个字在输入中的字节位置，最后一项为输入的字节长度
+func runeToByteOffsets(input []byte) []int {
+	offsets := make([]int, 0, len(input)+1)
+	for i := range string(input) {
+		offsets = append(offsets, i)
+	}
+	return append(offsets, len(input))
+}
+
+// 返回按files载入词典的分词器，files为空时返回SetSegmenter设置的分词器
+func segmenterFor(files string) (*sego.Segmenter, error) {
+	segmentersLock.Lock()
+	defer segmentersLock.Unlock()
+
+	if files == "" {
+		if defaultSegmenter == nil {
+			return nil, errors.New("sego: 没有调用SetSegmenter，也没有指定dictionary")
+		}
+		return defaultSegmenter, nil
+	}
+	if segmenter, ok := segmenters[files]; ok {
+		return segmenter, nil
+	}
+	// 使用返回错误的载入函数，以免文件无法读取时建索引的进程退出
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(files); err != nil {
+		return nil, err
+	}
+	segmenters[files] = segmenter
+	return segmenter, nil
+}
+
+// 分词器的构造函数，支持的配置项：
+//	dictionary	词典文件，多个文件用","分隔，为空时使用SetSegmenter设置的分词器
+//	search_mode	是否使用搜索模式，默认为false
+func TokenizerConstructor(config map[string]interface{}, cache *registry.Cache) (analysis.Tokenizer, error) {
+	files, _ := config["dictionary"].(string)
+	searchMode, _ := config["search_mode"].(bool)
+	segmenter, err := segmenterFor(strings.TrimSpace(files))
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenizer(segmenter, searchMode), nil
+}
+
+func analyzerConstructor(searchMode bool) registry.AnalyzerConstructor {
+	return func(config map[string]interface{}, cache *registry.Cache) (analysis.Analyzer, error) {
+		segmenter, err := segmenterFor("")
+		if err != nil {
+			return nil, err
+		}
+		// sego输出的分词已经转为小写，不需要再加to_lower过滤器
+		return &analysis.DefaultAnalyzer{Tokenizer: NewTokenizer(segmenter, searchMode)}, nil
+	}
+}
+
+func init() {
+	if err := registry.RegisterTokenizer(Name, TokenizerConstructor); err != nil {
+		panic(err)
+	}
+	if err := registry.RegisterAnalyzer(AnalyzerName, analyzerConstructor(false)); err != nil {
+		panic(err)
+	}
+	if err := registry.RegisterAnalyzer(SearchAnalyzerName, analyzerConstructor(true)); err != nil {
+		panic(err)
+	}
+}
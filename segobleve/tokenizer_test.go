@@ -0,0 +1,85 @@
+package segobleve
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blevesearch/bleve/v2/analysis"
+)
+
+const testDictionary = `中华 50 nz
+人民 100 n
+中华人民 200 nt
+`
+
+func writeTestDictionary(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "dictionary.txt")
+	if err := os.WriteFile(file, []byte(testDictionary), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// 只比较Term、Start、End、Position和Type
+func tokenFields(stream analysis.TokenStream) [][5]interface{} {
+	output := make([][5]interface{}, len(stream))
+	for i, token := range stream {
+		output[i] = [5]interface{}{string(token.Term), token.Start, token.End, token.Position, token.Type}
+	}
+	return output
+}
+
+func TestTokenize(t *testing.T) {
+	segmenter, err := segmenterFor(writeTestDictionary(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Start和End为字节位置，"中华人民"从第4个字节开始，每个汉字3个字节；空白不输出也不占用位置
+	input := []byte("abc 中华人民")
+
+	got := tokenFields(NewTokenizer(segmenter, false).Tokenize(input))
+	want := [][5]interface{}{
+		{"abc", 0, 3, 1, analysis.AlphaNumeric},
+		{"中华人民", 4, 16, 2, analysis.Ideographic},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() = %v, want %v", got, want)
+	}
+
+	// 搜索模式下子分词与分词本身位置相同
+	got = tokenFields(NewTokenizer(segmenter, true).Tokenize(input))
+	want = [][5]interface{}{
+		{"abc", 0, 3, 1, analysis.AlphaNumeric},
+		{"中华", 4, 10, 2, analysis.Ideographic},
+		{"人民", 10, 16, 2, analysis.Ideographic},
+		{"中华人民", 4, 16, 2, analysis.Ideographic},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Tokenize() in search mode = %v, want %v", got, want)
+	}
+}
+
+func TestTokenizerConstructor(t *testing.T) {
+	file := writeTestDictionary(t)
+	tokenizer, err := TokenizerConstructor(map[string]interface{}{"dictionary": file, "search_mode": true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(tokenizer.Tokenize([]byte("中华人民"))); n != 3 {
+		t.Errorf("Tokenize() in search mode returned %d tokens, want 3", n)
+	}
+
+	// 同一组词典只载入一次
+	first, _ := segmenterFor(file)
+	second, _ := segmenterFor(file)
+	if first != second {
+		t.Error("segmenterFor() loaded the same dictionary twice")
+	}
+
+	if _, err := TokenizerConstructor(map[string]interface{}{"dictionary": file + ".missing"}, nil); err == nil {
+		t.Error("TokenizerConstructor() with missing dictionary returned nil error")
+	}
+}
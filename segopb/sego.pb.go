@@ -0,0 +1,605 @@
+// sego分词的gRPC服务定义
+//
+// 修改后在仓库根目录重新生成segopb：
+//	protoc --go_out=. --go_opt=module=github.com/crossgit/sego \
+//	       --go-grpc_out=. --go-grpc_opt=module=github.com/crossgit/sego proto/sego.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        v5.29.3
+// source: proto/sego.proto
+
+package segopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SegmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// UTF8文本
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	// 搜索模式，为true时响应中同时包括分词及其子分词的词条
+	SearchMode    bool `protobuf:"varint,2,opt,name=search_mode,json=searchMode,proto3" json:"search_mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SegmentRequest) Reset() {
+	*x = SegmentRequest{}
+	mi := &file_proto_sego_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SegmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SegmentRequest) ProtoMessage() {}
+
+func (x *SegmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SegmentRequest.ProtoReflect.Descriptor instead.
+func (*SegmentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SegmentRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SegmentRequest) GetSearchMode() bool {
+	if x != nil {
+		return x.SearchMode
+	}
+	return false
+}
+
+// 一个分词，起止位置与Segment的Start()和End()一致
+type Segment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Pos           string                 `protobuf:"bytes,2,opt,name=pos,proto3" json:"pos,omitempty"`
+	Start         int32                  `protobuf:"varint,3,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,4,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Segment) Reset() {
+	*x = Segment{}
+	mi := &file_proto_sego_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Segment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Segment) ProtoMessage() {}
+
+func (x *Segment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Segment.ProtoReflect.Descriptor instead.
+func (*Segment) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Segment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Segment) GetPos() string {
+	if x != nil {
+		return x.Pos
+	}
+	return ""
+}
+
+func (x *Segment) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *Segment) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+// 搜索模式下的一个词条
+type SearchTerm struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Start         int32                  `protobuf:"varint,2,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,3,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchTerm) Reset() {
+	*x = SearchTerm{}
+	mi := &file_proto_sego_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchTerm) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchTerm) ProtoMessage() {}
+
+func (x *SearchTerm) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchTerm.ProtoReflect.Descriptor instead.
+func (*SearchTerm) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SearchTerm) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SearchTerm) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *SearchTerm) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type SegmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 精确模式的分词结果
+	Segments []*Segment `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	// 搜索模式下的词条，顺序与SegmentsToSlice的搜索模式相同
+	SearchTerms   []*SearchTerm `protobuf:"bytes,2,rep,name=search_terms,json=searchTerms,proto3" json:"search_terms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SegmentResponse) Reset() {
+	*x = SegmentResponse{}
+	mi := &file_proto_sego_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SegmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SegmentResponse) ProtoMessage() {}
+
+func (x *SegmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SegmentResponse.ProtoReflect.Descriptor instead.
+func (*SegmentResponse) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SegmentResponse) GetSegments() []*Segment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *SegmentResponse) GetSearchTerms() []*SearchTerm {
+	if x != nil {
+		return x.SearchTerms
+	}
+	return nil
+}
+
+type SegmentAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Segments      []*Segment             `protobuf:"bytes,1,rep,name=segments,proto3" json:"segments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SegmentAllResponse) Reset() {
+	*x = SegmentAllResponse{}
+	mi := &file_proto_sego_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SegmentAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SegmentAllResponse) ProtoMessage() {}
+
+func (x *SegmentAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SegmentAllResponse.ProtoReflect.Descriptor instead.
+func (*SegmentAllResponse) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SegmentAllResponse) GetSegments() []*Segment {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+type LookupRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Text          string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupRequest) Reset() {
+	*x = LookupRequest{}
+	mi := &file_proto_sego_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupRequest) ProtoMessage() {}
+
+func (x *LookupRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupRequest.ProtoReflect.Descriptor instead.
+func (*LookupRequest) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LookupRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// 分词的一个词性及该词性的频率
+type PosTag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pos           string                 `protobuf:"bytes,1,opt,name=pos,proto3" json:"pos,omitempty"`
+	Frequency     int64                  `protobuf:"varint,2,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PosTag) Reset() {
+	*x = PosTag{}
+	mi := &file_proto_sego_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PosTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PosTag) ProtoMessage() {}
+
+func (x *PosTag) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PosTag.ProtoReflect.Descriptor instead.
+func (*PosTag) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *PosTag) GetPos() string {
+	if x != nil {
+		return x.Pos
+	}
+	return ""
+}
+
+func (x *PosTag) GetFrequency() int64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+type LookupResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 分词是否在词典中，为false时其余字段为空
+	Found     bool   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Text      string `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Frequency int64  `protobuf:"varint,3,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	// 主词性
+	Pos string `protobuf:"bytes,4,opt,name=pos,proto3" json:"pos,omitempty"`
+	// 全部词性，第一个为主词性
+	Tags []*PosTag `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// 拼音，设置了拼音词典时才有
+	Pinyin        []string `protobuf:"bytes,6,rep,name=pinyin,proto3" json:"pinyin,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LookupResponse) Reset() {
+	*x = LookupResponse{}
+	mi := &file_proto_sego_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LookupResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LookupResponse) ProtoMessage() {}
+
+func (x *LookupResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_sego_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LookupResponse.ProtoReflect.Descriptor instead.
+func (*LookupResponse) Descriptor() ([]byte, []int) {
+	return file_proto_sego_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *LookupResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *LookupResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *LookupResponse) GetFrequency() int64 {
+	if x != nil {
+		return x.Frequency
+	}
+	return 0
+}
+
+func (x *LookupResponse) GetPos() string {
+	if x != nil {
+		return x.Pos
+	}
+	return ""
+}
+
+func (x *LookupResponse) GetTags() []*PosTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *LookupResponse) GetPinyin() []string {
+	if x != nil {
+		return x.Pinyin
+	}
+	return nil
+}
+
+var File_proto_sego_proto protoreflect.FileDescriptor
+
+const file_proto_sego_proto_rawDesc = "" +
+	"\n" +
+	"\x10proto/sego.proto\x12\asego.v1\"E\n" +
+	"\x0eSegmentRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x1f\n" +
+	"\vsearch_mode\x18\x02 \x01(\bR\n" +
+	"searchMode\"W\n" +
+	"\aSegment\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x10\n" +
+	"\x03pos\x18\x02 \x01(\tR\x03pos\x12\x14\n" +
+	"\x05start\x18\x03 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x04 \x01(\x05R\x03end\"H\n" +
+	"\n" +
+	"SearchTerm\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\x12\x14\n" +
+	"\x05start\x18\x02 \x01(\x05R\x05start\x12\x10\n" +
+	"\x03end\x18\x03 \x01(\x05R\x03end\"w\n" +
+	"\x0fSegmentResponse\x12,\n" +
+	"\bsegments\x18\x01 \x03(\v2\x10.sego.v1.SegmentR\bsegments\x126\n" +
+	"\fsearch_terms\x18\x02 \x03(\v2\x13.sego.v1.SearchTermR\vsearchTerms\"B\n" +
+	"\x12SegmentAllResponse\x12,\n" +
+	"\bsegments\x18\x01 \x03(\v2\x10.sego.v1.SegmentR\bsegments\"#\n" +
+	"\rLookupRequest\x12\x12\n" +
+	"\x04text\x18\x01 \x01(\tR\x04text\"8\n" +
+	"\x06PosTag\x12\x10\n" +
+	"\x03pos\x18\x01 \x01(\tR\x03pos\x12\x1c\n" +
+	"\tfrequency\x18\x02 \x01(\x03R\tfrequency\"\xa7\x01\n" +
+	"\x0eLookupResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x1c\n" +
+	"\tfrequency\x18\x03 \x01(\x03R\tfrequency\x12\x10\n" +
+	"\x03pos\x18\x04 \x01(\tR\x03pos\x12#\n" +
+	"\x04tags\x18\x05 \x03(\v2\x0f.sego.v1.PosTagR\x04tags\x12\x16\n" +
+	"\x06pinyin\x18\x06 \x03(\tR\x06pinyin2\x90\x02\n" +
+	"\tSegmenter\x12<\n" +
+	"\aSegment\x12\x17.sego.v1.SegmentRequest\x1a\x18.sego.v1.SegmentResponse\x12F\n" +
+	"\rSegmentStream\x12\x17.sego.v1.SegmentRequest\x1a\x18.sego.v1.SegmentResponse(\x010\x01\x12B\n" +
+	"\n" +
+	"SegmentAll\x12\x17.sego.v1.SegmentRequest\x1a\x1b.sego.v1.SegmentAllResponse\x129\n" +
+	"\x06Lookup\x12\x16.sego.v1.LookupRequest\x1a\x17.sego.v1.LookupResponseB!Z\x1fgithub.com/crossgit/sego/segopbb\x06proto3"
+
+var (
+	file_proto_sego_proto_rawDescOnce sync.Once
+	file_proto_sego_proto_rawDescData []byte
+)
+
+func file_proto_sego_proto_rawDescGZIP() []byte {
+	file_proto_sego_proto_rawDescOnce.Do(func() {
+		file_proto_sego_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_sego_proto_rawDesc), len(file_proto_sego_proto_rawDesc)))
+	})
+	return file_proto_sego_proto_rawDescData
+}
+
+var file_proto_sego_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_sego_proto_goTypes = []any{
+	(*SegmentRequest)(nil),     // 0: sego.v1.SegmentRequest
+	(*Segment)(nil),            // 1: sego.v1.Segment
+	(*SearchTerm)(nil),         // 2: sego.v1.SearchTerm
+	(*SegmentResponse)(nil),    // 3: sego.v1.SegmentResponse
+	(*SegmentAllResponse)(nil), // 4: sego.v1.SegmentAllResponse
+	(*LookupRequest)(nil),      // 5: sego.v1.LookupRequest
+	(*PosTag)(nil),             // 6: sego.v1.PosTag
+	(*LookupResponse)(nil),     // 7: sego.v1.LookupResponse
+}
+var file_proto_sego_proto_depIdxs = []int32{
+	1, // 0: sego.v1.SegmentResponse.segments:type_name -> sego.v1.Segment
+	2, // 1: sego.v1.SegmentResponse.search_terms:type_name -> sego.v1.SearchTerm
+	1, // 2: sego.v1.SegmentAllResponse.segments:type_name -> sego.v1.Segment
+	6, // 3: sego.v1.LookupResponse.tags:type_name -> sego.v1.PosTag
+	0, // 4: sego.v1.Segmenter.Segment:input_type -> sego.v1.SegmentRequest
+	0, // 5: sego.v1.Segmenter.SegmentStream:input_type -> sego.v1.SegmentRequest
+	0, // 6: sego.v1.Segmenter.SegmentAll:input_type -> sego.v1.SegmentRequest
+	5, // 7: sego.v1.Segmenter.Lookup:input_type -> sego.v1.LookupRequest
+	3, // 8: sego.v1.Segmenter.Segment:output_type -> sego.v1.SegmentResponse
+	3, // 9: sego.v1.Segmenter.SegmentStream:output_type -> sego.v1.SegmentResponse
+	4, // 10: sego.v1.Segmenter.SegmentAll:output_type -> sego.v1.SegmentAllResponse
+	7, // 11: sego.v1.Segmenter.Lookup:output_type -> sego.v1.LookupResponse
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_proto_sego_proto_init() }
+func file_proto_sego_proto_init() {
+	if File_proto_sego_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_sego_proto_rawDesc), len(file_proto_sego_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_sego_proto_goTypes,
+		DependencyIndexes: file_proto_sego_proto_depIdxs,
+		MessageInfos:      file_proto_sego_proto_msgTypes,
+	}.Build()
+	File_proto_sego_proto = out.File
+	file_proto_sego_proto_goTypes = nil
+	file_proto_sego_proto_depIdxs = nil
+}
@@ -0,0 +1,248 @@
+// sego分词的gRPC服务定义
+//
+// 修改后在仓库根目录重新生成segopb：
+//	protoc --go_out=. --go_opt=module=github.com/crossgit/sego \
+//	       --go-grpc_out=. --go-grpc_opt=module=github.com/crossgit/sego proto/sego.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: proto/sego.proto
+
+package segopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Segmenter_Segment_FullMethodName       = "/sego.v1.Segmenter/Segment"
+	Segmenter_SegmentStream_FullMethodName = "/sego.v1.Segmenter/SegmentStream"
+	Segmenter_SegmentAll_FullMethodName    = "/sego.v1.Segmenter/SegmentAll"
+	Segmenter_Lookup_FullMethodName        = "/sego.v1.Segmenter/Lookup"
+)
+
+// SegmenterClient is the client API for Segmenter service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 分词服务
+type SegmenterClient interface {
+	// 对一段文本分词
+	Segment(ctx context.Context, in *SegmentRequest, opts ...grpc.CallOption) (*SegmentResponse, error)
+	// 对多段文本流式分词，每个请求对应一个响应，顺序相同
+	SegmentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SegmentRequest, SegmentResponse], error)
+	// 全切分，输出所有可能的分词
+	SegmentAll(ctx context.Context, in *SegmentRequest, opts ...grpc.CallOption) (*SegmentAllResponse, error)
+	// 在词典中查找分词
+	Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error)
+}
+
+type segmenterClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSegmenterClient(cc grpc.ClientConnInterface) SegmenterClient {
+	return &segmenterClient{cc}
+}
+
+func (c *segmenterClient) Segment(ctx context.Context, in *SegmentRequest, opts ...grpc.CallOption) (*SegmentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SegmentResponse)
+	err := c.cc.Invoke(ctx, Segmenter_Segment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *segmenterClient) SegmentStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SegmentRequest, SegmentResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Segmenter_ServiceDesc.Streams[0], Segmenter_SegmentStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SegmentRequest, SegmentResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Segmenter_SegmentStreamClient = grpc.BidiStreamingClient[SegmentRequest, SegmentResponse]
+
+func (c *segmenterClient) SegmentAll(ctx context.Context, in *SegmentRequest, opts ...grpc.CallOption) (*SegmentAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SegmentAllResponse)
+	err := c.cc.Invoke(ctx, Segmenter_SegmentAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *segmenterClient) Lookup(ctx context.Context, in *LookupRequest, opts ...grpc.CallOption) (*LookupResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LookupResponse)
+	err := c.cc.Invoke(ctx, Segmenter_Lookup_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SegmenterServer is the server API for Segmenter service.
+// All implementations must embed UnimplementedSegmenterServer
+// for forward compatibility.
+//
+// 分词服务
+type SegmenterServer interface {
+	// 对一段文本分词
+	Segment(context.Context, *SegmentRequest) (*SegmentResponse, error)
+	// 对多段文本流式分词，每个请求对应一个响应，顺序相同
+	SegmentStream(grpc.BidiStreamingServer[SegmentRequest, SegmentResponse]) error
+	// 全切分，输出所有可能的分词
+	SegmentAll(context.Context, *SegmentRequest) (*SegmentAllResponse, error)
+	// 在词典中查找分词
+	Lookup(context.Context, *LookupRequest) (*LookupResponse, error)
+	mustEmbedUnimplementedSegmenterServer()
+}
+
+// UnimplementedSegmenterServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSegmenterServer struct{}
+
+func (UnimplementedSegmenterServer) Segment(context.Context, *SegmentRequest) (*SegmentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Segment not implemented")
+}
+func (UnimplementedSegmenterServer) SegmentStream(grpc.BidiStreamingServer[SegmentRequest, SegmentResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SegmentStream not implemented")
+}
+func (UnimplementedSegmenterServer) SegmentAll(context.Context, *SegmentRequest) (*SegmentAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SegmentAll not implemented")
+}
+func (UnimplementedSegmenterServer) Lookup(context.Context, *LookupRequest) (*LookupResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Lookup not implemented")
+}
+func (UnimplementedSegmenterServer) mustEmbedUnimplementedSegmenterServer() {}
+func (UnimplementedSegmenterServer) testEmbeddedByValue()                   {}
+
+// UnsafeSegmenterServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SegmenterServer will
+// result in compilation errors.
+type UnsafeSegmenterServer interface {
+	mustEmbedUnimplementedSegmenterServer()
+}
+
+func RegisterSegmenterServer(s grpc.ServiceRegistrar, srv SegmenterServer) {
+	// If the following call pancis, it indicates UnimplementedSegmenterServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Segmenter_ServiceDesc, srv)
+}
+
+func _Segmenter_Segment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SegmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SegmenterServer).Segment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Segmenter_Segment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SegmenterServer).Segment(ctx, req.(*SegmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Segmenter_SegmentStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(SegmenterServer).SegmentStream(&grpc.GenericServerStream[SegmentRequest, SegmentResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Segmenter_SegmentStreamServer = grpc.BidiStreamingServer[SegmentRequest, SegmentResponse]
+
+func _Segmenter_SegmentAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SegmentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SegmenterServer).SegmentAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Segmenter_SegmentAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SegmenterServer).SegmentAll(ctx, req.(*SegmentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Segmenter_Lookup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LookupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SegmenterServer).Lookup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Segmenter_Lookup_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SegmenterServer).Lookup(ctx, req.(*LookupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Segmenter_ServiceDesc is the grpc.ServiceDesc for Segmenter service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Segmenter_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "sego.v1.Segmenter",
+	HandlerType: (*SegmenterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Segment",
+			Handler:    _Segmenter_Segment_Handler,
+		},
+		{
+			MethodName: "SegmentAll",
+			Handler:    _Segmenter_SegmentAll_Handler,
+		},
+		{
+			MethodName: "Lookup",
+			Handler:    _Segmenter_Lookup_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SegmentStream",
+			Handler:       _Segmenter_SegmentStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/sego.proto",
+}
@@ -0,0 +1,354 @@
+// 分词的HTTP服务，请求和响应均为JSON
+//
+// 提供以下接口，均只接受POST请求：
+//	/segment	精确模式分词
+//	/search		搜索模式分词，输出分词及其子分词的词条
+//	/segment_all	全切分，输出所有可能的分词
+//	/keywords	关键词提取，method为"tfidf"（默认）或"textrank"
+//	/reload		重新载入词典，载入完成后原子地替换分词器，载入期间旧的分词器继续服务
+//	/_analyze	与Elasticsearch的_analyze接口相同，analyzer为"ik_smart"或"ik_max_word"，
+//			也接受GET请求
+//
+// 分词接口的请求体为{"text": "..."}，或用{"texts": ["...", ...]}批量分词，
+// 批量请求的响应为{"results": [...]}，顺序与texts相同。
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/keywords"
+)
+
+const (
+	defaultMaxBodyBytes = 1 << 20
+	defaultMaxBatchSize = 100
+	defaultTopK         = 10
+)
+
+// 服务的配置
+type Options struct {
+	// 词典文件，多个文件用","分隔，与Segmenter.LoadDictionary相同
+	Dictionary string
+
+	// 停用词文件，多个文件用","分隔，设置后/segment和/search的结果以及提取的关键词中
+	// 去掉停用词，为空时不过滤停用词
+	StopWords string
+
+	// TFIDF使用的IDF表文件，多个文件用","分隔，为空时由词典词频推算
+	IDF string
+
+	// 请求体的最大字节数，为0时为1MB
+	MaxBodyBytes int64
+
+	// 批量请求中文本的最大个数，为0时为100
+	MaxBatchSize int
+}
+
+// 分词服务，实现了http.Handler
+type Server struct {
+	options Options
+	mux     *http.ServeMux
+
+	// 当前使用的分词器等，重新载入词典时整体替换
+	engine atomic.Value
+
+	// 保证同一时刻只有一次重新载入
+	reloadLock sync.Mutex
+}
+
+// 一次载入的分词器和关键词提取器，载入后只读
+type engine struct {
+	segmenter *sego.Segmenter
+	tfidf     *keywords.TFIDF
+	textRank  *keywords.TextRank
+}
+
+// 按options载入词典并新建服务
+func New(options Options) (*Server, error) {
+	if options.MaxBodyBytes <= 0 {
+		options.MaxBodyBytes = defaultMaxBodyBytes
+	}
+	if options.MaxBatchSize <= 0 {
+		options.MaxBatchSize = defaultMaxBatchSize
+	}
+	server := &Server{options: options, mux: http.NewServeMux()}
+	if err := server.Reload(); err != nil {
+		return nil, err
+	}
+
+	server.mux.HandleFunc("/segment", server.handleSegment)
+	server.mux.HandleFunc("/search", server.handleSearch)
+	server.mux.HandleFunc("/segment_all", server.handleSegmentAll)
+	server.mux.HandleFunc("/keywords", server.handleKeywords)
+	server.mux.HandleFunc("/reload", server.handleReload)
+	server.mux.HandleFunc("/_analyze", server.handleAnalyze)
+	return server, nil
+}
+
+// 重新载入词典、停用词和IDF表，失败时继续使用原来的分词器
+func (s *Server) Reload() error {
+	s.reloadLock.Lock()
+	defer s.reloadLock.Unlock()
+
+	e, err := loadEngine(s.options)
+	if err != nil {
+		return err
+	}
+	s.engine.Store(e)
+	return nil
+}
+
+func loadEngine(options Options) (*engine, error) {
+	if options.Dictionary == "" {
+		return nil, errors.New("没有指定词典文件")
+	}
+
+	// 使用返回错误的载入函数，以免文件无法读取时重新载入让服务退出
+	segmenter := new(sego.Segmenter)
+	if err := segmenter.LoadDictionaryE(options.Dictionary); err != nil {
+		return nil, err
+	}
+	if options.StopWords != "" {
+		if err := segmenter.LoadStopWordsE(options.StopWords); err != nil {
+			return nil, err
+		}
+	}
+
+	e := &engine{
+		segmenter: segmenter,
+		tfidf:     keywords.NewTFIDF(segmenter),
+		textRank:  keywords.NewTextRank(segmenter),
+	}
+	if options.IDF != "" {
+		if err := e.tfidf.LoadIDFFile(options.IDF); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+func (s *Server) currentEngine() *engine {
+	return s.engine.Load().(*engine)
+}
+
+// 实现http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// 分词接口的请求
+type Request struct {
+	// 单个文本
+	Text string `json:"text"`
+
+	// 批量请求的多个文本，设置后忽略Text
+	Texts []string `json:"texts"`
+
+	// 关键词接口的参数：返回的关键词个数（默认为10）和提取方法
+	TopK   int    `json:"top_k"`
+	Method string `json:"method"`
+}
+
+// 一个分词
+type Segment struct {
+	Text  string `json:"text"`
+	Pos   string `json:"pos"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// 批量请求的响应
+type BatchResponse struct {
+	Results []interface{} `json:"results"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (s *Server) handleSegment(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(e *engine, text string, request *Request) (interface{}, error) {
+		segs := e.segmenter.SegmentFiltered([]byte(text))
+		output := make([]Segment, len(segs))
+		for i := range segs {
+			output[i] = Segment{
+				Text:  segs[i].Token().Text(),
+				Pos:   segs[i].Pos(),
+				Start: segs[i].Start(),
+				End:   segs[i].End(),
+			}
+		}
+		return output, nil
+	})
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(e *engine, text string, request *Request) (interface{}, error) {
+		terms := sego.SearchTerms(e.segmenter.Segment([]byte(text)))
+		output := make([]sego.SearchTerm, 0, len(terms))
+		for _, term := range terms {
+			// 与SegmentsToSliceFiltered相同，子分词中的停用词也去掉
+			if !e.segmenter.IsStopWord(term.Text) {
+				output = append(output, term)
+			}
+		}
+		return output, nil
+	})
+}
+
+func (s *Server) handleSegmentAll(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(e *engine, text string, request *Request) (interface{}, error) {
+		cuts := e.segmenter.SegmentAll([]byte(text))
+		output := make([]Segment, len(cuts))
+		for i, cut := range cuts {
+			output[i] = Segment{Text: cut.Token, Pos: cut.Pos, Start: cut.Start, End: cut.End}
+		}
+		return output, nil
+	})
+}
+
+func (s *Server) handleKeywords(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(e *engine, text string, request *Request) (interface{}, error) {
+		topK := request.TopK
+		if topK <= 0 {
+			topK = defaultTopK
+		}
+		switch request.Method {
+		case "", "tfidf":
+			return e.tfidf.Extract([]byte(text), topK), nil
+		case "textrank":
+			return e.textRank.Extract([]byte(text), topK), nil
+		}
+		return nil, fmt.Errorf("未知的关键词提取方法 \"%s\"", request.Method)
+	})
+}
+
+// Elasticsearch _analyze接口的请求，text为字符串或字符串数组
+type AnalyzeRequest struct {
+	Analyzer string          `json:"analyzer"`
+	Text     json.RawMessage `json:"text"`
+}
+
+// Elasticsearch对数组形式的text默认的位置间隔和偏移间隔
+const (
+	analyzePositionGap = 100
+	analyzeOffsetGap   = 1
+)
+
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "只接受GET或POST请求")
+		return
+	}
+
+	var request AnalyzeRequest
+	if !s.decode(w, r, &request) {
+		return
+	}
+	mode, err := sego.ParseAnalyzeMode(request.Analyzer)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var texts []string
+	if err := json.Unmarshal(request.Text, &texts); err != nil {
+		var text string
+		if err := json.Unmarshal(request.Text, &text); err != nil {
+			writeError(w, http.StatusBadRequest, "text应为字符串或字符串数组")
+			return
+		}
+		texts = []string{text}
+	}
+	writeJSON(w, http.StatusOK,
+		s.currentEngine().segmenter.AnalyzeTexts(texts, mode, analyzePositionGap, analyzeOffsetGap))
+}
+
+func (s *Server) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "只接受POST请求")
+		return
+	}
+	if err := s.Reload(); err != nil {
+		log.Printf("重新载入词典失败: %s", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"tokens": s.currentEngine().segmenter.Dictionary().NumTokens()})
+}
+
+// 解析请求并对每个文本调用process，整个请求使用同一个分词器，不受并发的重新载入影响
+func (s *Server) handle(w http.ResponseWriter, r *http.Request,
+	process func(e *engine, text string, request *Request) (interface{}, error)) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "只接受POST请求")
+		return
+	}
+
+	var request Request
+	if !s.decode(w, r, &request) {
+		return
+	}
+	if len(request.Texts) > s.options.MaxBatchSize {
+		writeError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("批量请求最多%d个文本", s.options.MaxBatchSize))
+		return
+	}
+
+	e := s.currentEngine()
+	if request.Texts == nil {
+		result, err := process(e, request.Text, &request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	response := BatchResponse{Results: make([]interface{}, len(request.Texts))}
+	for i, text := range request.Texts {
+		result, err := process(e, text, &request)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Results[i] = result
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// 解析JSON请求体，失败时写出错误响应并返回false
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.options.MaxBodyBytes))
+	if err := decoder.Decode(request); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "请求体超过限制")
+			return false
+		}
+		writeError(w, http.StatusBadRequest, "无法解析请求: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		log.Printf("无法写出响应: %s", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
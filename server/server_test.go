@@ -0,0 +1,221 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/crossgit/sego"
+	"github.com/crossgit/sego/keywords"
+)
+
+const testDictionary = `中华 50 nz
+人民 100 n
+共和国 60 n
+中华人民共和国 20 ns
+发展 80 v 20 vn
+`
+
+// 新建使用测试词典的服务，返回服务和词典文件名
+func newTestServer(t *testing.T, options Options) (*Server, string) {
+	t.Helper()
+	dir := t.TempDir()
+	options.Dictionary = writeTestFile(t, dir, "dictionary.txt", testDictionary)
+	if options.StopWords != "" {
+		options.StopWords = writeTestFile(t, dir, "stop.txt", options.StopWords)
+	}
+	if options.IDF != "" {
+		options.IDF = writeTestFile(t, dir, "idf.txt", options.IDF)
+	}
+	server, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return server, options.Dictionary
+}
+
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// 发出请求，状态码不是status时报错，并把响应解析到response中
+func do(t *testing.T, server *Server, method, path, body string, status int, response interface{}) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	if recorder.Code != status {
+		t.Fatalf("%s %s = %d %s, want %d", method, path, recorder.Code, recorder.Body, status)
+	}
+	if response != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), response); err != nil {
+			t.Fatalf("%s %s: %s", method, path, err)
+		}
+	}
+}
+
+func TestSegment(t *testing.T) {
+	server, _ := newTestServer(t, Options{})
+
+	var segments []Segment
+	do(t, server, "POST", "/segment", `{"text": "中华人民共和国发展"}`, http.StatusOK, &segments)
+	want := []Segment{{"中华人民共和国", "ns", 0, 7}, {"发展", "v", 7, 9}}
+	if !reflect.DeepEqual(segments, want) {
+		t.Errorf("/segment = %v, want %v", segments, want)
+	}
+
+	// 批量请求的结果与texts的顺序相同
+	var batch struct {
+		Results [][]Segment `json:"results"`
+	}
+	do(t, server, "POST", "/segment", `{"texts": ["发展", "人民", ""]}`, http.StatusOK, &batch)
+	wantBatch := [][]Segment{{{"发展", "v", 0, 2}}, {{"人民", "n", 0, 2}}, {}}
+	if !reflect.DeepEqual(batch.Results, wantBatch) {
+		t.Errorf("/segment batch = %v, want %v", batch.Results, wantBatch)
+	}
+
+	var terms []sego.SearchTerm
+	do(t, server, "POST", "/search", `{"text": "中华人民共和国"}`, http.StatusOK, &terms)
+	wantTerms := []sego.SearchTerm{
+		{Text: "中华", Start: 0, End: 2},
+		{Text: "人民", Start: 2, End: 4},
+		{Text: "共和国", Start: 4, End: 7},
+		{Text: "中华人民共和国", Start: 0, End: 7},
+	}
+	if !reflect.DeepEqual(terms, wantTerms) {
+		t.Errorf("/search = %v, want %v", terms, wantTerms)
+	}
+
+	do(t, server, "POST", "/segment_all", `{"text": "人民共和国"}`, http.StatusOK, &segments)
+	want = []Segment{{"人民", "n", 0, 2}, {"共和国", "n", 2, 5}}
+	if !reflect.DeepEqual(segments, want) {
+		t.Errorf("/segment_all = %v, want %v", segments, want)
+	}
+}
+
+func TestStopWords(t *testing.T) {
+	server, _ := newTestServer(t, Options{StopWords: "发展\n人民\n"})
+
+	var segments []Segment
+	do(t, server, "POST", "/segment", `{"text": "中华人民共和国发展"}`, http.StatusOK, &segments)
+	if want := []Segment{{"中华人民共和国", "ns", 0, 7}}; !reflect.DeepEqual(segments, want) {
+		t.Errorf("/segment = %v, want %v", segments, want)
+	}
+
+	// 子分词中的停用词也去掉
+	var terms []sego.SearchTerm
+	do(t, server, "POST", "/search", `{"text": "中华人民共和国"}`, http.StatusOK, &terms)
+	var texts []string
+	for _, term := range terms {
+		texts = append(texts, term.Text)
+	}
+	if want := []string{"中华", "共和国", "中华人民共和国"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("/search = %q, want %q", texts, want)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	server, _ := newTestServer(t, Options{MaxBatchSize: 2, MaxBodyBytes: 64})
+
+	do(t, server, "POST", "/segment", `{"texts": ["人民", "发展"]}`, http.StatusOK, nil)
+	do(t, server, "POST", "/segment", `{"texts": ["人民", "发展", "中华"]}`, http.StatusRequestEntityTooLarge, nil)
+	do(t, server, "POST", "/segment", `{"text": "`+strings.Repeat("人民", 20)+`"}`,
+		http.StatusRequestEntityTooLarge, nil)
+	do(t, server, "POST", "/_analyze", `{"text": "`+strings.Repeat("人民", 20)+`"}`,
+		http.StatusRequestEntityTooLarge, nil)
+	do(t, server, "POST", "/segment", `{"text": `, http.StatusBadRequest, nil)
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	server, _ := newTestServer(t, Options{})
+	for _, path := range []string{"/segment", "/search", "/segment_all", "/keywords", "/reload"} {
+		do(t, server, "GET", path, `{"text": "人民"}`, http.StatusMethodNotAllowed, nil)
+	}
+	analyze := `{"analyzer": "ik_smart", "text": "人民"}`
+	do(t, server, "PUT", "/_analyze", analyze, http.StatusMethodNotAllowed, nil)
+	do(t, server, "GET", "/_analyze", analyze, http.StatusOK, nil)
+}
+
+func TestKeywords(t *testing.T) {
+	server, _ := newTestServer(t, Options{IDF: "人民 1\n发展 3\n"})
+	request := func(method string, topK int) string {
+		return fmt.Sprintf(`{"text": "人民 发展 人民", "method": "%s", "top_k": %d}`, method, topK)
+	}
+
+	// 人民 2/3*1，发展 1/3*3
+	var result []keywords.Keyword
+	for _, method := range []string{"", "tfidf"} {
+		do(t, server, "POST", "/keywords", request(method, 0), http.StatusOK, &result)
+		if len(result) != 2 || result[0].Text != "发展" || result[1].Text != "人民" {
+			t.Errorf("/keywords method %q = %v, want [发展 人民]", method, result)
+		}
+	}
+	do(t, server, "POST", "/keywords", request("tfidf", 1), http.StatusOK, &result)
+	if len(result) != 1 || result[0].Text != "发展" {
+		t.Errorf("/keywords top_k 1 = %v, want [发展]", result)
+	}
+	do(t, server, "POST", "/keywords", request("textrank", 0), http.StatusOK, &result)
+	if len(result) != 2 {
+		t.Errorf("/keywords method textrank = %v, want 2 keywords", result)
+	}
+	do(t, server, "POST", "/keywords", request("lda", 0), http.StatusBadRequest, nil)
+}
+
+func TestAnalyze(t *testing.T) {
+	server, _ := newTestServer(t, Options{})
+
+	var response sego.AnalyzeResponse
+	do(t, server, "POST", "/_analyze", `{"analyzer": "ik_smart", "text": "人民"}`, http.StatusOK, &response)
+	want := []sego.AnalyzeToken{{Token: "人民", StartOffset: 0, EndOffset: 2, Type: "CN_WORD", Position: 0}}
+	if !reflect.DeepEqual(response.Tokens, want) {
+		t.Errorf("/_analyze string = %v, want %v", response.Tokens, want)
+	}
+
+	// 数组中每段文本的位置和偏移依次累加
+	do(t, server, "POST", "/_analyze", `{"analyzer": "ik_smart", "text": ["人民", "发展"]}`, http.StatusOK, &response)
+	want = append(want, sego.AnalyzeToken{
+		Token: "发展", StartOffset: 3, EndOffset: 5, Type: "CN_WORD", Position: 1 + analyzePositionGap})
+	if !reflect.DeepEqual(response.Tokens, want) {
+		t.Errorf("/_analyze array = %v, want %v", response.Tokens, want)
+	}
+
+	do(t, server, "POST", "/_analyze", `{"analyzer": "ik_smart", "text": 1}`, http.StatusBadRequest, nil)
+	do(t, server, "POST", "/_analyze", `{"analyzer": "standard", "text": "人民"}`, http.StatusBadRequest, nil)
+}
+
+func TestReload(t *testing.T) {
+	server, dictionary := newTestServer(t, Options{})
+
+	var reloaded map[string]int
+	do(t, server, "POST", "/reload", "", http.StatusOK, &reloaded)
+	if reloaded["tokens"] != 5 {
+		t.Errorf("/reload tokens = %d, want 5", reloaded["tokens"])
+	}
+
+	writeTestFile(t, filepath.Dir(dictionary), filepath.Base(dictionary), testDictionary+"经济 100 n\n")
+	do(t, server, "POST", "/reload", "", http.StatusOK, &reloaded)
+	if reloaded["tokens"] != 6 {
+		t.Errorf("/reload tokens = %d, want 6", reloaded["tokens"])
+	}
+
+	// 载入失败时继续使用原来的分词器
+	if err := os.Remove(dictionary); err != nil {
+		t.Fatal(err)
+	}
+	do(t, server, "POST", "/reload", "", http.StatusInternalServerError, nil)
+	var segments []Segment
+	do(t, server, "POST", "/segment", `{"text": "经济发展"}`, http.StatusOK, &segments)
+	if want := []Segment{{"经济", "n", 0, 2}, {"发展", "v", 2, 4}}; !reflect.DeepEqual(segments, want) {
+		t.Errorf("/segment after failed reload = %v, want %v", segments, want)
+	}
+}
@@ -0,0 +1,115 @@
+package sego
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// 从文件中载入停用词表，每行一个停用词
+//
+// 可以载入多个文件，文件名用","分隔，多次调用时停用词累加。文件无法读取时调用log.Fatalf
+// 退出程序，需要处理错误时使用LoadStopWordsE。
+// 与LoadDictionary一样，停用词表应在分词器被并发使用之前载入，见AddStopWords。
+func (seg *Segmenter) LoadStopWords(files string) {
+	if err := seg.LoadStopWordsE(files); err != nil {
+		log.Fatalf("%s\n", err)
+	}
+}
+
+// 与LoadStopWords相同，但文件无法读取时返回错误而不是退出程序，此前的文件中的停用词已经载入
+func (seg *Segmenter) LoadStopWordsE(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		log.Printf("载入sego停用词表 %s", file)
+		stopFile, err := os.Open(file)
+		if err != nil {
+			return fmt.Errorf("无法载入停用词文件 \"%s\": %s", file, err)
+		}
+		err = seg.LoadStopWordsFromReader(stopFile)
+		stopFile.Close()
+		if err != nil {
+			return fmt.Errorf("无法读取停用词文件 \"%s\": %s", file, err)
+		}
+	}
+	return nil
+}
+
+// 从reader中载入停用词表，每行一个停用词
+func (seg *Segmenter) LoadStopWordsFromReader(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if word := strings.TrimSpace(scanner.Text()); word != "" {
+			seg.AddStopWords(word)
+		}
+	}
+	return scanner.Err()
+}
+
+// 载入内置的默认停用词表
+func (seg *Segmenter) LoadDefaultStopWords() {
+	seg.AddStopWords(strings.Fields(defaultStopWords)...)
+}
+
+// 添加停用词
+//
+// 停用词表没有加锁，所有载入和添加停用词的方法都必须在分词器被多个goroutine使用之前调用；
+// 载入完成后IsStopWord、FilterStopWords等只读方法可以并发调用。
+func (seg *Segmenter) AddStopWords(words ...string) {
+	if seg.stopWords == nil {
+		seg.stopWords = make(map[string]bool)
+	}
+	for _, word := range words {
+		// 与splitTextToWords一致，英文停用词按小写匹配
+		seg.stopWords[string(toLower([]byte(word)))] = true
+	}
+}
+
+// 判断一个分词文本是否为停用词
+func (seg *Segmenter) IsStopWord(word string) bool {
+	return seg.stopWords[word]
+}
+
+// 返回去掉停用词后的分词，输入的分词结果保持不变
+func (seg *Segmenter) FilterStopWords(segs []Segment) []Segment {
+	output := make([]Segment, 0, len(segs))
+	for _, s := range segs {
+		if !seg.stopWords[s.token.Text()] {
+			output = append(output, s)
+		}
+	}
+	return output
+}
+
+// 对文本分词并去掉停用词，需要原始分词结果时使用Segment
+func (seg *Segmenter) SegmentFiltered(bytes []byte) []Segment {
+	return seg.FilterStopWords(seg.Segment(bytes))
+}
+
+// 与SegmentsToSlice相同，但去掉停用词，搜索模式下子分词中的停用词也会去掉
+func (seg *Segmenter) SegmentsToSliceFiltered(segs []Segment, searchMode bool) []string {
+	output := make([]string, 0, len(segs))
+	for _, word := range SegmentsToSlice(segs, searchMode) {
+		if !seg.stopWords[word] {
+			output = append(output, word)
+		}
+	}
+	return output
+}
+
+// 内置的默认停用词表，包括常见的虚词、代词和标点
+const defaultStopWords = `
+的 了 在 是 我 有 和 就 不 人 都 一 一个 上 也 很 到 说 要 去 你 会 着 没有 看 好 自己 这
+那 他 她 它 们 我们 你们 他们 她们 它们 这个 那个 这些 那些 这里 那里 这样 那样 什么 怎么
+为什么 哪 哪里 哪个 谁 之 与 及 或 或者 而 而且 并 并且 但 但是 然而 因为 所以 因此 如果
+虽然 即使 只要 只有 除了 对于 关于 由于 为了 把 被 让 给 从 向 往 对 于 以 为 用 按 按照
+根据 通过 经过 将 已 已经 曾经 正在 还 又 再 才 只 就是 还是 也是 都是 可以 可能 应该 能够
+能 会 要 得 地 吗 呢 吧 啊 呀 哦 嗯 哈 嘛 么 啦 之后 之前 以后 以前 以及 其 其中 其他 其它
+此 此外 等 等等 各 各种 每 某 该 本 另 另外 比 比如 例如 一些 一样 一般 一直 一起 非常 更
+最 太 越 较 比较 十分 特别 相当 如此 如何 怎样 这么 那么 多少 几 所 所有 任何 无论 不管
+不仅 不但 而是 只是 于是 然后 接着 总之 如 若 即 即便 便 则 却 且 并非
+， 。 、 ； ： ？ ！ “ ” ‘ ’ （ ） 《 》 【 】 … — ～ · , . ; : ? ! " ' ( ) [ ] < > - ~
+the a an and or of to in on at for with by from is are was were be been it this that
+`
@@ -0,0 +1,39 @@
+package sego
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStopWordsFiltered(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"中国 100 ns",
+		"人民 100 n",
+		"中国人民 50 nt",
+		"的 100 uj",
+	))
+	seg.AddStopWords("的", "中国", "The")
+
+	text := []byte("中国人民的The")
+	segs := seg.Segment(text)
+	all := SegmentsToSlice(segs, false)
+
+	if got := SegmentsToSlice(seg.SegmentFiltered(text), false); !reflect.DeepEqual(got, []string{"中国人民"}) {
+		t.Errorf("SegmentFiltered() = %q, want [中国人民]", got)
+	}
+	if got := seg.SegmentsToSliceFiltered(segs, false); !reflect.DeepEqual(got, []string{"中国人民"}) {
+		t.Errorf("SegmentsToSliceFiltered(false) = %q, want [中国人民]", got)
+	}
+	// 搜索模式下子分词中的停用词也去掉
+	if got := seg.SegmentsToSliceFiltered(segs, true); !reflect.DeepEqual(got, []string{"人民", "中国人民"}) {
+		t.Errorf("SegmentsToSliceFiltered(true) = %q, want [人民 中国人民]", got)
+	}
+	// 输入的分词结果保持不变
+	if got := SegmentsToSlice(segs, false); !reflect.DeepEqual(got, all) {
+		t.Errorf("segments changed to %q, want %q", got, all)
+	}
+	if !reflect.DeepEqual(all, []string{"中国人民", "的", "the"}) {
+		t.Errorf("SegmentsToSlice() = %q, want [中国人民 的 the]", all)
+	}
+}
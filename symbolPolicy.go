@@ -0,0 +1,111 @@
+package sego
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// 空白字元的处理方式
+type WhitespaceMode int
+
+const (
+	// 保留每个空白字元，作为词性为"x"的分词输出（默认）
+	KeepWhitespace WhitespaceMode = iota
+
+	// 丢弃所有空白字元
+	DropWhitespace
+
+	// 将连续的空白字元合并为一个分词输出
+	MergeWhitespace
+)
+
+// 空白与标点的处理策略
+//
+// 默认情况下空格、换行和标点与未登录的汉字一样作为词性为"x"的分词输出，
+// 设置策略后可以在Segment中直接区分或过滤这些分词，调用方无需再检查分词文本。
+type SymbolPolicy struct {
+	// 空白字元的处理方式
+	Whitespace WhitespaceMode
+
+	// 空白分词的词性，为空时沿用"x"
+	WhitespacePos string
+
+	// 标点符号的词性，比如"w"，为空时沿用"x"
+	PunctuationPos string
+}
+
+// 设置分词器的空白与标点处理策略
+func (seg *Segmenter) SetSymbolPolicy(policy SymbolPolicy) {
+	seg.symbolPolicy = policy
+}
+
+// 返回分词器的空白与标点处理策略
+func (seg *Segmenter) SymbolPolicy() SymbolPolicy {
+	return seg.symbolPolicy
+}
+
+// 按照策略处理分词结果中的空白和标点
+func (seg *Segmenter) applySymbolPolicy(segments []Segment) []Segment {
+	policy := seg.symbolPolicy
+	if policy == (SymbolPolicy{}) {
+		return segments
+	}
+
+	output := segments[:0]
+	for i := 0; i < len(segments); i++ {
+		s := segments[i]
+		switch symbolKind(s.token) {
+		case symbolWhitespace:
+			if policy.Whitespace == DropWhitespace {
+				continue
+			}
+			// 合并模式下吸收后续所有的空白分词
+			text := s.token.text
+			if policy.Whitespace == MergeWhitespace {
+				for i+1 < len(segments) && symbolKind(segments[i+1].token) == symbolWhitespace {
+					i++
+					text = append(append([]Text{}, text...), segments[i].token.text...)
+					s.end = segments[i].end
+				}
+			}
+			if len(text) > 1 || policy.WhitespacePos != "" {
+				s.token = newSymbolToken(text, policy.WhitespacePos)
+			}
+		case symbolPunctuation:
+			if policy.PunctuationPos != "" {
+				s.token = newSymbolToken(s.token.text, policy.PunctuationPos)
+			}
+		}
+		output = append(output, s)
+	}
+	return output
+}
+
+const (
+	symbolNone = iota
+	symbolWhitespace
+	symbolPunctuation
+)
+
+// 判断一个伪分词是空白、标点还是其它字元，词典中的分词总是返回symbolNone
+func symbolKind(token *Token) int {
+	if token.pos != "x" || len(token.text) != 1 {
+		return symbolNone
+	}
+	r, _ := utf8.DecodeRune(token.text[0])
+	switch {
+	case unicode.IsSpace(r):
+		return symbolWhitespace
+	case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		return symbolPunctuation
+	}
+	return symbolNone
+}
+
+// 生成空白或标点对应的伪分词，pos为空时沿用"x"
+func newSymbolToken(text []Text, pos string) *Token {
+	if pos == "" {
+		pos = "x"
+	}
+	return &Token{text: text, frequency: 1, distance: 32, pos: pos}
+}
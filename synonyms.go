@@ -0,0 +1,165 @@
+package sego
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+)
+
+// 同义词词典，用于在搜索模式下扩展分词，比如"电脑"同时输出"计算机"
+type SynonymDictionary struct {
+	synonyms map[string][]string
+}
+
+// 新建一个空的同义词词典
+func NewSynonymDictionary() *SynonymDictionary {
+	return &SynonymDictionary{synonyms: make(map[string][]string)}
+}
+
+// 添加一组互为同义词的分词
+func (dict *SynonymDictionary) Add(words ...string) {
+	for _, word := range words {
+		dict.AddOneWay(word, words...)
+	}
+}
+
+// 添加单向的同义词，word会扩展出synonyms，反之不成立
+func (dict *SynonymDictionary) AddOneWay(word string, synonyms ...string) {
+	word = strings.ToLower(word)
+	for _, synonym := range synonyms {
+		synonym = strings.ToLower(synonym)
+		if synonym == word || dict.has(word, synonym) {
+			continue
+		}
+		dict.synonyms[word] = append(dict.synonyms[word], synonym)
+	}
+}
+
+func (dict *SynonymDictionary) has(word, synonym string) bool {
+	for _, s := range dict.synonyms[word] {
+		if s == synonym {
+			return true
+		}
+	}
+	return false
+}
+
+// 从reader中载入同义词，每行一组，分词之间用空白或","分隔，比如
+//	电脑,计算机,微机
+// 用"=>"分隔时为单向同义词，左边的分词扩展出右边的分词：
+//	笔记本 => 笔记本电脑,手提电脑
+func (dict *SynonymDictionary) Load(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "=>"); i >= 0 {
+			for _, word := range splitSynonyms(line[:i]) {
+				dict.AddOneWay(word, splitSynonyms(line[i+2:])...)
+			}
+			continue
+		}
+		if words := splitSynonyms(line); len(words) > 1 {
+			dict.Add(words...)
+		}
+	}
+	return scanner.Err()
+}
+
+// 从文件中载入同义词，多个文件名用","分隔
+func (dict *SynonymDictionary) LoadFile(files string) error {
+	for _, file := range strings.Split(files, ",") {
+		synonymFile, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		err = dict.Load(synonymFile)
+		synonymFile.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitSynonyms(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '，' || r == ' ' || r == '\t'
+	})
+}
+
+// 返回分词的同义词，不包括分词本身
+func (dict *SynonymDictionary) Synonyms(word string) []string {
+	if dict == nil {
+		return nil
+	}
+	return dict.synonyms[word]
+}
+
+// 与SegmentsToSlice相同，搜索模式下在每个分词后追加其同义词
+func (dict *SynonymDictionary) SegmentsToSlice(segs []Segment, searchMode bool) []string {
+	words := SegmentsToSlice(segs, searchMode)
+	if !searchMode {
+		return words
+	}
+	output := make([]string, 0, len(words))
+	for _, word := range words {
+		output = append(output, word)
+		output = append(output, dict.Synonyms(word)...)
+	}
+	return output
+}
+
+// 搜索模式下的一个词条
+type SearchTerm struct {
+	// 词条文本
+	Text string `json:"text"`
+
+	// 词条在文本中的起止位置，与Segment的Start()和End()一致
+	Start int `json:"start"`
+	End   int `json:"end"`
+
+	// 是否为同义词扩展注入的词条，注入的词条与原词条位置相同，
+	// 建立索引时不应增加位置计数
+	Synonym bool `json:"synonym,omitempty"`
+}
+
+// 输出搜索模式下的所有词条及其位置，顺序与SegmentsToSlice的搜索模式相同
+func SearchTerms(segs []Segment) []SearchTerm {
+	return searchTerms(segs, nil)
+}
+
+// 与SearchTerms相同，并在每个词条后追加其同义词
+func (dict *SynonymDictionary) SearchTerms(segs []Segment) []SearchTerm {
+	return searchTerms(segs, dict)
+}
+
+func searchTerms(segs []Segment, dict *SynonymDictionary) (output []SearchTerm) {
+	for _, seg := range segs {
+		output = tokenToTerms(output, seg.token, seg.start, dict)
+	}
+	return
+}
+
+// 按tokenToSlice的顺序输出分词及其子分词的词条，offset为分词在文本中的起始位置
+func tokenToTerms(output []SearchTerm, token *Token, offset int, dict *SynonymDictionary) []SearchTerm {
+	hasOnlyTerminalToken := true
+	for _, s := range token.segments {
+		if len(s.token.segments) > 1 {
+			hasOnlyTerminalToken = false
+		}
+	}
+	if !hasOnlyTerminalToken {
+		for _, s := range token.segments {
+			output = tokenToTerms(output, s.token, offset+s.start, dict)
+		}
+	}
+
+	text := textSliceToString(token.text)
+	end := offset + textSliceByteLength(token.text)
+	output = append(output, SearchTerm{Text: text, Start: offset, End: end})
+	for _, synonym := range dict.Synonyms(text) {
+		output = append(output, SearchTerm{Text: synonym, Start: offset, End: end, Synonym: true})
+	}
+	return output
+}
@@ -0,0 +1,45 @@
+package sego
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSynonymSearchTerms(t *testing.T) {
+	var seg Segmenter
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"我 100 r",
+		"的 100 uj",
+		"电脑 100 n",
+		"坏 100 v",
+	))
+	dict := NewSynonymDictionary()
+	if err := dict.Load(strings.NewReader("电脑,计算机\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	segs := seg.Segment([]byte("我的电脑坏"))
+	want := []SearchTerm{
+		{Text: "我", Start: 0, End: 1},
+		{Text: "的", Start: 1, End: 2},
+		{Text: "电脑", Start: 2, End: 4},
+		{Text: "计算机", Start: 2, End: 4, Synonym: true},
+		{Text: "坏", Start: 4, End: 5},
+	}
+	if got := dict.SearchTerms(segs); !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchTerms() = %v, want %v", got, want)
+	}
+	if got := SearchTerms(segs); !reflect.DeepEqual(got, append(want[:3:3], want[4])) {
+		t.Errorf("SearchTerms() without synonyms = %v", got)
+	}
+
+	words := []string{"我", "的", "电脑", "计算机", "坏"}
+	if got := dict.SegmentsToSlice(segs, true); !reflect.DeepEqual(got, words) {
+		t.Errorf("SegmentsToSlice(true) = %q, want %q", got, words)
+	}
+	// 普通模式不扩展
+	if got := dict.SegmentsToSlice(segs, false); !reflect.DeepEqual(got, []string{"我", "的", "电脑", "坏"}) {
+		t.Errorf("SegmentsToSlice(false) = %q", got)
+	}
+}
@@ -0,0 +1,344 @@
+package sego
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// 词性类别，输出函数按类别而不是具体的词性字串筛选分词
+type TagCategory int
+
+const (
+	// 没有特殊含义的词性
+	TagOther TagCategory = iota
+
+	// 名词，SegmentsOutputSingle和SegmentsOutputArray输出该类分词
+	TagNoun
+
+	// 助词，SegmentsOutput中模式的起始分词
+	TagAuxiliary
+
+	// 介词，SegmentsOutput中模式的结束分词
+	TagPreposition
+
+	// 未登录字元，比如分词器补加的"x"伪分词
+	TagUnknown
+
+	// 标点符号
+	TagPunctuation
+
+	// 需要删除的分词，见SegmentsMergeOutput的注释
+	TagDeletion
+)
+
+var tagCategoryNames = [...]string{
+	TagOther:       "other",
+	TagNoun:        "noun",
+	TagAuxiliary:   "auxiliary",
+	TagPreposition: "preposition",
+	TagUnknown:     "unknown",
+	TagPunctuation: "punctuation",
+	TagDeletion:    "deletion",
+}
+
+func (c TagCategory) String() string {
+	if c >= 0 && int(c) < len(tagCategoryNames) {
+		return tagCategoryNames[c]
+	}
+	return fmt.Sprintf("TagCategory(%d)", int(c))
+}
+
+// 一个词性标注及其描述信息
+type Tag struct {
+	// 词性字串，比如"n"；以前缀声明时为前缀
+	Name string
+
+	// 词性的中文描述，比如"名词"
+	Description string
+
+	// 词性类别
+	Category TagCategory
+}
+
+// 词性标注集
+//
+// 标注集声明了词典中允许出现的词性及其描述，并为每个词性指定类别。
+// 封闭的标注集在载入词典时校验词性，开放的标注集接受任意词性字串。
+type TagSet struct {
+	name     string
+	open     bool
+	tags     map[string]Tag
+	prefixes []Tag                 // 以前缀声明的词性，按前缀长度从长到短排列
+	entities map[string]EntityType // 表示人名、地名、机构名的词性
+}
+
+// 新建一个封闭的标注集
+func NewTagSet(name string, tags ...Tag) *TagSet {
+	ts := &TagSet{name: name, tags: make(map[string]Tag), entities: make(map[string]EntityType)}
+	for _, tag := range tags {
+		ts.Add(tag)
+	}
+	return ts
+}
+
+// 返回标注集名称
+func (ts *TagSet) Name() string {
+	return ts.name
+}
+
+// 设置标注集是否开放，开放的标注集不校验词性
+func (ts *TagSet) SetOpen(open bool) {
+	ts.open = open
+}
+
+// 标注集是否开放
+func (ts *TagSet) Open() bool {
+	return ts.open
+}
+
+// 向标注集中加入一个词性，同名词性会被覆盖
+func (ts *TagSet) Add(tag Tag) {
+	ts.tags[tag.Name] = tag
+}
+
+// 声明所有以prefix开头的词性都属于同一类别，精确声明的词性优先
+func (ts *TagSet) AddPrefix(tag Tag) {
+	ts.prefixes = append(ts.prefixes, tag)
+	sort.SliceStable(ts.prefixes, func(i, j int) bool {
+		return len(ts.prefixes[i].Name) > len(ts.prefixes[j].Name)
+	})
+}
+
+// 查找词性的描述信息，先精确匹配，再按最长前缀匹配
+func (ts *TagSet) Lookup(pos string) (Tag, bool) {
+	if tag, ok := ts.tags[pos]; ok {
+		return tag, true
+	}
+	for _, tag := range ts.prefixes {
+		if strings.HasPrefix(pos, tag.Name) {
+			return tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// 声明词性表示某一类命名实体，EntityRecognizer把词典中标注为该词性的分词直接作为实体
+func (ts *TagSet) AddEntity(pos string, t EntityType) {
+	ts.entities[pos] = t
+}
+
+// 返回词性表示的命名实体类型，不表示命名实体时返回false
+func (ts *TagSet) Entity(pos string) (EntityType, bool) {
+	t, ok := ts.entities[pos]
+	return t, ok
+}
+
+// 返回词性所属类别，未声明的词性返回TagOther
+func (ts *TagSet) Category(pos string) TagCategory {
+	tag, _ := ts.Lookup(pos)
+	return tag.Category
+}
+
+// 判断词性是否属于某一类别
+func (ts *TagSet) Is(pos string, category TagCategory) bool {
+	tag, ok := ts.Lookup(pos)
+	return ok && tag.Category == category
+}
+
+// 校验词性是否在标注集中，空词性（词典中没有词性标注）总是合法的
+func (ts *TagSet) Validate(pos string) error {
+	if pos == "" || ts.open {
+		return nil
+	}
+	if _, ok := ts.Lookup(pos); !ok {
+		return fmt.Errorf("词性 \"%s\" 不在标注集 %s 中", pos, ts.name)
+	}
+	return nil
+}
+
+// 返回标注集中精确声明的所有词性，按词性字串排序
+func (ts *TagSet) Tags() []Tag {
+	output := make([]Tag, 0, len(ts.tags))
+	for _, tag := range ts.tags {
+		output = append(output, tag)
+	}
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Name < output[j].Name
+	})
+	return output
+}
+
+// 设置分词器载入词典时使用的标注集，为nil时不校验词性
+func (seg *Segmenter) SetTagSet(ts *TagSet) {
+	seg.tagSet = ts
+}
+
+// 返回分词器使用的标注集
+func (seg *Segmenter) TagSet() *TagSet {
+	if seg.tagSet == nil {
+		return DefaultTagSet
+	}
+	return seg.tagSet
+}
+
+// 默认标注集，保持各输出函数原有的词性约定：
+// "n"为名词，"u"为助词，"p"为介词，"x"为未登录字元，以"d"开头的词性表示删除。
+// 该标注集是开放的，接受任意词性。
+var DefaultTagSet = newDefaultTagSet()
+
+func newDefaultTagSet() *TagSet {
+	ts := NewTagSet("default",
+		Tag{"n", "名词", TagNoun},
+		Tag{"u", "助词", TagAuxiliary},
+		Tag{"p", "介词", TagPreposition},
+		Tag{"x", "未登录字元", TagUnknown},
+	)
+	ts.AddPrefix(Tag{"d", "删除词", TagDeletion})
+	addICTCLASEntities(ts)
+	ts.SetOpen(true)
+	return ts
+}
+
+// 声明ICTCLAS中表示人名、地名、机构名的词性
+func addICTCLASEntities(ts *TagSet) {
+	for _, pos := range []string{"nr", "nrf", "nrj"} {
+		ts.AddEntity(pos, EntityPerson)
+	}
+	for _, pos := range []string{"ns", "nsf"} {
+		ts.AddEntity(pos, EntityPlace)
+	}
+	ts.AddEntity("nt", EntityOrganization)
+}
+
+func init() {
+	addICTCLASEntities(ICTCLASTagSet)
+	PKUTagSet.AddEntity("nr", EntityPerson)
+	PKUTagSet.AddEntity("ns", EntityPlace)
+	PKUTagSet.AddEntity("nt", EntityOrganization)
+}
+
+// 计算所汉语词性标注集（ICTCLAS）
+var ICTCLASTagSet = NewTagSet("ictclas",
+	Tag{"n", "名词", TagNoun},
+	Tag{"nr", "人名", TagNoun},
+	Tag{"nr1", "汉语姓氏", TagNoun},
+	Tag{"nr2", "汉语名字", TagNoun},
+	Tag{"nrj", "日语人名", TagNoun},
+	Tag{"nrf", "音译人名", TagNoun},
+	Tag{"ns", "地名", TagNoun},
+	Tag{"nsf", "音译地名", TagNoun},
+	Tag{"nt", "机构团体名", TagNoun},
+	Tag{"nz", "其它专名", TagNoun},
+	Tag{"nl", "名词性惯用语", TagNoun},
+	Tag{"ng", "名词性语素", TagNoun},
+	Tag{"t", "时间词", TagOther},
+	Tag{"tg", "时间词性语素", TagOther},
+	Tag{"s", "处所词", TagOther},
+	Tag{"f", "方位词", TagOther},
+	Tag{"v", "动词", TagOther},
+	Tag{"vd", "副动词", TagOther},
+	Tag{"vn", "名动词", TagOther},
+	Tag{"vshi", "动词“是”", TagOther},
+	Tag{"vyou", "动词“有”", TagOther},
+	Tag{"vf", "趋向动词", TagOther},
+	Tag{"vx", "形式动词", TagOther},
+	Tag{"vi", "不及物动词", TagOther},
+	Tag{"vl", "动词性惯用语", TagOther},
+	Tag{"vg", "动词性语素", TagOther},
+	Tag{"a", "形容词", TagOther},
+	Tag{"ad", "副形词", TagOther},
+	Tag{"an", "名形词", TagOther},
+	Tag{"ag", "形容词性语素", TagOther},
+	Tag{"al", "形容词性惯用语", TagOther},
+	Tag{"b", "区别词", TagOther},
+	Tag{"bl", "区别词性惯用语", TagOther},
+	Tag{"z", "状态词", TagOther},
+	Tag{"r", "代词", TagOther},
+	Tag{"rr", "人称代词", TagOther},
+	Tag{"rz", "指示代词", TagOther},
+	Tag{"ry", "疑问代词", TagOther},
+	Tag{"rg", "代词性语素", TagOther},
+	Tag{"m", "数词", TagOther},
+	Tag{"mq", "数量词", TagOther},
+	Tag{"q", "量词", TagOther},
+	Tag{"qv", "动量词", TagOther},
+	Tag{"qt", "时量词", TagOther},
+	Tag{"d", "副词", TagOther},
+	Tag{"p", "介词", TagPreposition},
+	Tag{"pba", "介词“把”", TagPreposition},
+	Tag{"pbei", "介词“被”", TagPreposition},
+	Tag{"c", "连词", TagOther},
+	Tag{"cc", "并列连词", TagOther},
+	Tag{"u", "助词", TagAuxiliary},
+	Tag{"uzhe", "着", TagAuxiliary},
+	Tag{"ule", "了 喽", TagAuxiliary},
+	Tag{"uguo", "过", TagAuxiliary},
+	Tag{"ude1", "的 底", TagAuxiliary},
+	Tag{"ude2", "地", TagAuxiliary},
+	Tag{"ude3", "得", TagAuxiliary},
+	Tag{"usuo", "所", TagAuxiliary},
+	Tag{"udeng", "等 等等 云云", TagAuxiliary},
+	Tag{"uyy", "一样 一般 似的 般", TagAuxiliary},
+	Tag{"udh", "的话", TagAuxiliary},
+	Tag{"uls", "来讲 来说 而言 说来", TagAuxiliary},
+	Tag{"uzhi", "之", TagAuxiliary},
+	Tag{"ulian", "连", TagAuxiliary},
+	Tag{"e", "叹词", TagOther},
+	Tag{"y", "语气词", TagOther},
+	Tag{"o", "拟声词", TagOther},
+	Tag{"h", "前缀", TagOther},
+	Tag{"k", "后缀", TagOther},
+	Tag{"x", "字符串", TagUnknown},
+	Tag{"xx", "非语素字", TagUnknown},
+	Tag{"xu", "网址URL", TagUnknown},
+	Tag{"w", "标点符号", TagPunctuation},
+)
+
+// 北大人民日报语料库词性标注集（PKU）
+var PKUTagSet = NewTagSet("pku",
+	Tag{"Ag", "形语素", TagOther},
+	Tag{"a", "形容词", TagOther},
+	Tag{"ad", "副形词", TagOther},
+	Tag{"an", "名形词", TagOther},
+	Tag{"b", "区别词", TagOther},
+	Tag{"Bg", "区别语素", TagOther},
+	Tag{"c", "连词", TagOther},
+	Tag{"d", "副词", TagOther},
+	Tag{"Dg", "副语素", TagOther},
+	Tag{"e", "叹词", TagOther},
+	Tag{"f", "方位词", TagOther},
+	Tag{"g", "语素", TagOther},
+	Tag{"h", "前接成分", TagOther},
+	Tag{"i", "成语", TagOther},
+	Tag{"j", "简称略语", TagOther},
+	Tag{"k", "后接成分", TagOther},
+	Tag{"l", "习用语", TagOther},
+	Tag{"m", "数词", TagOther},
+	Tag{"Mg", "数语素", TagOther},
+	Tag{"n", "名词", TagNoun},
+	Tag{"Ng", "名语素", TagNoun},
+	Tag{"nr", "人名", TagNoun},
+	Tag{"ns", "地名", TagNoun},
+	Tag{"nt", "机构团体", TagNoun},
+	Tag{"nx", "外文字符", TagNoun},
+	Tag{"nz", "其他专名", TagNoun},
+	Tag{"o", "拟声词", TagOther},
+	Tag{"p", "介词", TagPreposition},
+	Tag{"q", "量词", TagOther},
+	Tag{"r", "代词", TagOther},
+	Tag{"Rg", "代语素", TagOther},
+	Tag{"s", "处所词", TagOther},
+	Tag{"t", "时间词", TagOther},
+	Tag{"Tg", "时语素", TagOther},
+	Tag{"u", "助词", TagAuxiliary},
+	Tag{"v", "动词", TagOther},
+	Tag{"vd", "副动词", TagOther},
+	Tag{"vn", "名动词", TagOther},
+	Tag{"Vg", "动语素", TagOther},
+	Tag{"w", "标点符号", TagPunctuation},
+	Tag{"x", "非语素字", TagUnknown},
+	Tag{"y", "语气词", TagOther},
+	Tag{"Yg", "语气语素", TagOther},
+	Tag{"z", "状态词", TagOther},
+)
@@ -6,6 +6,12 @@ package sego
 //	3. 一段文字，比如"中国有十三亿人口"
 type Text []byte
 
+// 分词的一个词性及其在语料库中的频率
+type TokenPos struct {
+	Pos       string
+	Frequency int
+}
+
 // 一个分词
 type Token struct {
 	// 分词的字串，这实际上是个字元数组
@@ -19,9 +25,15 @@ type Token struct {
 	// sum(distance(分词))的最小值，这就是“最短路径”的来历。
 	distance float32
 
-	// 词性标注
+	// 词性标注，即tags中的第一个词性
 	pos string
 
+	// 分词的所有词性及各词性的频率
+	tags []TokenPos
+
+	// 分词的拼音，每个汉字一个音节，见PinyinDictionary
+	pinyin []string
+
 	// 该分词文本的进一步分词划分，见Segments函数注释。
 	segments []*Segment
 }
@@ -48,6 +60,29 @@ func (token *Token) Pos() string {
 	return token.pos
 }
 
+// 返回分词的所有词性及各词性在语料库中的频率，第一个为主词性
+func (token *Token) PosTags() []TokenPos {
+	if len(token.tags) == 0 && token.pos != "" {
+		return []TokenPos{{Pos: token.pos, Frequency: token.frequency}}
+	}
+	return token.tags
+}
+
+// 返回分词的拼音，分词器没有设置拼音词典时返回nil
+func (token *Token) Pinyin() []string {
+	return token.pinyin
+}
+
+// 判断分词是否可以标注为某一词性
+func (token *Token) HasPos(pos string) bool {
+	for _, tag := range token.PosTags() {
+		if tag.Pos == pos {
+			return true
+		}
+	}
+	return false
+}
+
 // 该分词文本的进一步分词划分，比如"中华人民共和国中央人民政府"这个分词
 // 有两个子分词"中华人民共和国"和"中央人民政府"。子分词也可以进一步有子分词
 // 形成一个树结构，遍历这个树就可以得到该分词的所有细致分词划分，这主要
@@ -2,7 +2,6 @@ package sego
 
 import (
 	"bytes"
-	"fmt"
 	"strings"
 )
 
@@ -15,37 +14,10 @@ import (
 //      "中华/nz 人民/n 共和/nz 共和国/ns 人民共和国/nt 中华人民共和国/ns "
 //
 // 搜索模式主要用于给搜索引擎提供尽可能多的关键字，详情请见Token结构体的注释。
-func SegmentsToString(segs []Segment, searchMode bool) (output string) {
-	if searchMode {
-		for _, seg := range segs {
-			output += tokenToString(seg.token)
-		}
-	} else {
-		for _, seg := range segs {
-			output += fmt.Sprintf(
-				"%s/%s ", textSliceToString(seg.token.text), seg.token.pos)
-		}
-	}
-	return
-}
-
-func tokenToString(token *Token) (output string) {
-	hasOnlyTerminalToken := true
-	for _, s := range token.segments {
-		if len(s.token.segments) > 1 {
-			hasOnlyTerminalToken = false
-		}
-	}
-
-	if !hasOnlyTerminalToken {
-		for _, s := range token.segments {
-			if s != nil {
-				output += tokenToString(s.token)
-			}
-		}
-	}
-	output += fmt.Sprintf("%s/%s ", textSliceToString(token.text), token.pos)
-	return
+func SegmentsToString(segs []Segment, searchMode bool) string {
+	var output strings.Builder
+	WriteSegments(&output, segs, searchMode)
+	return output.String()
 }
 
 // 输出分词结果到一个字符串slice
@@ -151,22 +123,28 @@ type Output struct {
 	Pos    string
 }
 
-// SegmentsOutput 输出到map里
+// SegmentsOutput 输出到map里，词性约定见DefaultTagSet
+// 更一般的抽取规则可以用Pattern定义，"(/u) ~5 (/p)"与mOffset为5时的结果近似，见Pattern
 func SegmentsOutput(segs []Segment, lOffset, mOffset, rOffset int) []Output {
+	return DefaultTagSet.SegmentsOutput(segs, lOffset, mOffset, rOffset)
+}
+
+// SegmentsOutput 按标注集的助词(TagAuxiliary)和介词(TagPreposition)类别匹配
+func (ts *TagSet) SegmentsOutput(segs []Segment, lOffset, mOffset, rOffset int) []Output {
 	output := make([]Output, 0)
 	segsLen := len(segs)
 	for i := 0; i < segsLen-1; {
 		nseg := segs[i]
-		npos := nseg.token.pos
+		npos := nseg.Pos()
 		xtoken := make([]string, 0)
-		if npos == "u" {
+		if ts.Is(npos, TagAuxiliary) {
 			for j := i + 1; j < segsLen; j++ {
 				pseg := segs[j]
-				ppos := pseg.token.pos
-				if ppos == "u" {
+				ppos := pseg.Pos()
+				if ts.Is(ppos, TagAuxiliary) {
 					i = j
 					break
-				} else if ppos == "p" {
+				} else if ts.Is(ppos, TagPreposition) {
 					nend := nseg.End()
 					pstart := pseg.Start()
 					if pstart-nend > mOffset {
@@ -222,12 +200,17 @@ type OutputSingle struct {
 // SegmentsOutputSingle 单一的输出
 // 输入 左右边距
 func SegmentsOutputSingle(segs []Segment, lOffset, rOffset int) []OutputSingle {
+	return DefaultTagSet.SegmentsOutputSingle(segs, lOffset, rOffset)
+}
+
+// SegmentsOutputSingle 输出标注集中名词(TagNoun)类别的分词
+func (ts *TagSet) SegmentsOutputSingle(segs []Segment, lOffset, rOffset int) []OutputSingle {
 	output := make([]OutputSingle, 0)
 	segsLen := len(segs)
 	for i := 0; i < segsLen; i++ {
 		nseg := segs[i]
-		npos := nseg.token.pos
-		if npos == "n" {
+		npos := nseg.Pos()
+		if ts.Is(npos, TagNoun) {
 			var info OutputSingle
 			info.Start = nseg.Start()
 			info.End = nseg.End()
@@ -253,13 +236,18 @@ func SegmentsOutputSingle(segs []Segment, lOffset, rOffset int) []OutputSingle {
 // SegmentsOutputAll 单一的输出
 // 输入 左右边距
 func SegmentsOutputAll(segs []Segment, lOffset, rOffset int) []OutputSingle {
+	return DefaultTagSet.SegmentsOutputAll(segs, lOffset, rOffset)
+}
+
+// SegmentsOutputAll 输出除未登录字元(TagUnknown)外的所有分词
+func (ts *TagSet) SegmentsOutputAll(segs []Segment, lOffset, rOffset int) []OutputSingle {
 	output := make([]OutputSingle, 0)
 	segsLen := len(segs)
 	for i := 0; i < segsLen; i++ {
 		nseg := segs[i]
 		// log.Println("获取的时候:", tokenToStr(nseg.token))
-		npos := nseg.token.pos
-		if npos != "x" {
+		npos := nseg.Pos()
+		if !ts.Is(npos, TagUnknown) {
 			var info OutputSingle
 			info.Start = nseg.Start()
 			info.End = nseg.End()
@@ -285,12 +273,17 @@ func SegmentsOutputAll(segs []Segment, lOffset, rOffset int) []OutputSingle {
 // SegmentsOutputSingleAll 单一的输出
 // 输入 忽略左右边距,去除x,全部输出
 func SegmentsOutputSingleAll(segs []Segment) []OutputSingle {
+	return DefaultTagSet.SegmentsOutputSingleAll(segs)
+}
+
+// SegmentsOutputSingleAll 去除未登录字元(TagUnknown)和删除词(TagDeletion)后全部输出
+func (ts *TagSet) SegmentsOutputSingleAll(segs []Segment) []OutputSingle {
 	output := make([]OutputSingle, 0)
 	segsLen := len(segs)
 	for i := 0; i < segsLen; i++ {
 		nseg := segs[i]
-		npos := nseg.token.pos
-		if npos != "x" && npos[:1] != "d" {
+		npos := nseg.Pos()
+		if !ts.Is(npos, TagUnknown) && !ts.Is(npos, TagDeletion) {
 			var info OutputSingle
 			info.Start = nseg.Start()
 			info.End = nseg.End()
@@ -362,90 +355,16 @@ func SegmentsAllOutput(segs []CutAll) []OutputSingle {
 
 // SegmentsMergeOutput 全切的合并后输出
 // 输入 忽略左右边距,去除x,全部输出
+// 合并规则见MergePolicy的注释
 func SegmentsMergeOutput(segs []CutAll) []OutputSingle {
-	// log.Println("segs0:", segs)
-	output := make([]OutputSingle, 0)
-	segsLen := len(segs)
-	if segsLen == 0 {
-		return output
-	}
-
-	indexSeg := segs[0]
-	output = append(output, OutputSingle{
-		Start:  indexSeg.Start,
-		End:    indexSeg.End,
-		NToken: indexSeg.Token,
-		Pos:    indexSeg.Pos,
-	})
-	if segsLen == 1 {
-		return output
-	}
-	for i := 0; i < segsLen; i++ {
-		lastOne := output[len(output)-1] //合并最后一个
-		nseg := segs[i]
-
-		if nseg.Start > lastOne.End {
-			if lastOne.Pos[:1] == "d" {
-				output[len(output)-1].Start = nseg.Start
-				output[len(output)-1].End = nseg.End
-				output[len(output)-1].NToken = nseg.Token
-				output[len(output)-1].Pos = nseg.Pos
-			} else {
-				output = append(output, OutputSingle{
-					Start:  nseg.Start,
-					End:    nseg.End,
-					NToken: nseg.Token,
-					Pos:    nseg.Pos,
-				})
-			}
-		} else {
-			if lastOne.End > nseg.Start && nseg.End > lastOne.End {
-				// 相交
-				if nseg.Pos[:1] == "d" {
-					// 要删除的词都不添加,并且前一个词也会变成要删除词
-					output[len(output)-1].Pos = nseg.Pos
-					continue
-				}
-				if lastOne.Pos[:1] != "d" && nseg.Pos == lastOne.Pos {
-					// 向后延续 // ABCD:n CDEF:n -> ABCDEF:n
-					output[len(output)-1].End = nseg.End
-					output[len(output)-1].NToken = lastOne.NToken + string([]rune(nseg.Token)[lastOne.End-nseg.Start:])
-					continue
-				}
-				output[len(output)-1].Start = nseg.Start
-				output[len(output)-1].End = nseg.End
-				output[len(output)-1].NToken = nseg.Token
-				output[len(output)-1].Pos = nseg.Pos
-
-				// 删除词和一般词拼接,还是删除词
-				continue
-			}
-			if lastOne.End == nseg.Start && nseg.End > lastOne.End {
-				// 不相交
-				if nseg.Pos[:1] != "d" {
-					if nseg.Pos == lastOne.Pos {
-						// 向后延续 // ABCD:n EFGH:n -> ABCDEFGH:n
-						output[len(output)-1].End = nseg.End
-						output[len(output)-1].NToken = lastOne.NToken + string([]rune(nseg.Token)[lastOne.End-nseg.Start:])
-						continue
-					}
-					output = append(output, OutputSingle{
-						Start:  nseg.Start,
-						End:    nseg.End,
-						NToken: nseg.Token,
-						Pos:    nseg.Pos,
-					})
-
-					continue
-				}
-				continue
-			}
+	return DefaultTagSet.SegmentsMergeOutput(segs)
+}
 
-			continue
-		}
-	}
-	// log.Println("==", output)
-	return output
+// SegmentsMergeOutput 全切的合并后输出，删除词由标注集的TagDeletion类别决定
+func (ts *TagSet) SegmentsMergeOutput(segs []CutAll) []OutputSingle {
+	policy := DefaultMergePolicy()
+	policy.Tags = ts
+	return policy.Merge(segs)
 }
 
 func segmentArrayToStr(segs []Segment) (output string) {
@@ -457,12 +376,17 @@ func segmentArrayToStr(segs []Segment) (output string) {
 
 // SegmentsOutputArray 只输出存在的词
 func SegmentsOutputArray(segs []Segment) []string {
+	return DefaultTagSet.SegmentsOutputArray(segs)
+}
+
+// SegmentsOutputArray 只输出标注集中名词(TagNoun)类别的词
+func (ts *TagSet) SegmentsOutputArray(segs []Segment) []string {
 	output := make([]string, 0)
 	segsLen := len(segs)
 	for i := 0; i < segsLen; i++ {
 		nseg := segs[i]
-		npos := nseg.token.pos
-		if npos == "n" {
+		npos := nseg.Pos()
+		if ts.Is(npos, TagNoun) {
 			output = append(output, tokenToStr(nseg.token))
 
 		}
@@ -0,0 +1,197 @@
+package sego
+
+import (
+	"sort"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	defaultUnknownMinLength   = 2
+	defaultUnknownMaxLength   = 6
+	defaultUnknownMaxContexts = 3
+	defaultUnknownContextSize = 10
+	defaultUnknownMaxWords    = 100000
+)
+
+// 未登录词收集器收集到的一个候选词
+type UnknownWord struct {
+	// 连续的未登录单字拼接成的文本
+	Text string
+
+	// 出现次数
+	Count int
+
+	// 最先出现的几处上下文，候选词用"[]"标出，比如"今天在[鄂尔多斯]开会"
+	Contexts []string
+}
+
+// 未登录词收集器
+//
+// 词典中没有的字在分词结果中是词性属于TagUnknown类别（DefaultTagSet中为"x"）
+// 的单字，连续出现的这类单字往往是词典缺少的词，比如人名、地名和领域术语。
+// 收集器跨多次Segment调用统计这些单字串的出现次数和上下文，供人工审核后用
+// WriteDictionary写入用户词典。
+// 标点、空白、英文和数字不计入。收集器可以被多个goroutine并发使用。
+type UnknownWordCollector struct {
+	lock        sync.Mutex
+	words       map[string]*UnknownWord
+	minLength   int
+	maxLength   int
+	maxContexts int
+	contextSize int
+	maxWords    int
+}
+
+// 新建收集器，默认收集2到6个字的单字串，每个记录3处上下文，上下文左右各10个字，
+// 最多记录100000个不同的单字串
+func NewUnknownWordCollector() *UnknownWordCollector {
+	return &UnknownWordCollector{
+		words:       make(map[string]*UnknownWord),
+		minLength:   defaultUnknownMinLength,
+		maxLength:   defaultUnknownMaxLength,
+		maxContexts: defaultUnknownMaxContexts,
+		contextSize: defaultUnknownContextSize,
+		maxWords:    defaultUnknownMaxWords,
+	}
+}
+
+// 设置收集的单字串的字数范围，超过maxLength个字的单字串不收集
+func (c *UnknownWordCollector) SetLengthRange(minLength, maxLength int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.minLength, c.maxLength = minLength, maxLength
+}
+
+// 设置每个候选词记录的上下文个数和上下文左右的字数
+func (c *UnknownWordCollector) SetContexts(maxContexts, contextSize int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxContexts, c.contextSize = maxContexts, contextSize
+}
+
+// 设置最多记录的不同单字串个数，达到上限后不再收集新的单字串，已有的继续计数
+func (c *UnknownWordCollector) SetMaxWords(n int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.maxWords = n
+}
+
+// 设置分词器使用的未登录词收集器，为nil时不收集
+func (seg *Segmenter) SetUnknownWordCollector(collector *UnknownWordCollector) {
+	seg.unknownWords = collector
+}
+
+// 返回出现次数不少于minCount的候选词，按出现次数从多到少排列
+func (c *UnknownWordCollector) Words(minCount int) []UnknownWord {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	output := make([]UnknownWord, 0)
+	for _, word := range c.words {
+		if word.Count >= minCount {
+			output = append(output, UnknownWord{
+				Text:     word.Text,
+				Count:    word.Count,
+				Contexts: append([]string(nil), word.Contexts...),
+			})
+		}
+	}
+	sort.Slice(output, func(i, j int) bool {
+		if output[i].Count != output[j].Count {
+			return output[i].Count > output[j].Count
+		}
+		return output[i].Text < output[j].Text
+	})
+	return output
+}
+
+// 返回出现次数不少于minCount的候选词对应的词典条目，频率为出现次数，词性为pos，
+// 可以用WriteDictionary写成用户词典
+func (c *UnknownWordCollector) Entries(minCount int, pos string) []DictionaryEntry {
+	words := c.Words(minCount)
+	entries := make([]DictionaryEntry, len(words))
+	for i, word := range words {
+		entries[i] = DictionaryEntry{Text: word.Text, Tags: []TokenPos{{Pos: pos, Frequency: word.Count}}}
+	}
+	return entries
+}
+
+// 删除候选词，比如已经加入用户词典的词，候选词不存在时返回false
+func (c *UnknownWordCollector) Remove(text string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if _, ok := c.words[text]; !ok {
+		return false
+	}
+	delete(c.words, text)
+	return true
+}
+
+// 清空收集到的所有候选词
+func (c *UnknownWordCollector) Reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.words = make(map[string]*UnknownWord)
+}
+
+// 收集分词结果中连续的未登录单字，bytes为分词的原文，ts为分词器的标注集
+func (c *UnknownWordCollector) collect(ts *TagSet, bytes []byte, segments []Segment) {
+	var runes []rune
+	start := -1
+	for i := 0; i <= len(segments); i++ {
+		if i < len(segments) && isUnknownChar(ts, segments[i].token) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start < 0 {
+			continue
+		}
+		run := segments[start:i]
+		start = -1
+
+		c.lock.Lock()
+		if len(run) < c.minLength || len(run) > c.maxLength {
+			c.lock.Unlock()
+			continue
+		}
+		text := segmentArrayToStr(run)
+		word, ok := c.words[text]
+		if !ok {
+			if len(c.words) >= c.maxWords {
+				c.lock.Unlock()
+				continue
+			}
+			word = &UnknownWord{Text: text}
+			c.words[text] = word
+		}
+		word.Count++
+		if len(word.Contexts) < c.maxContexts {
+			if runes == nil {
+				runes = []rune(string(bytes))
+			}
+			word.Contexts = append(word.Contexts,
+				unknownContext(runes, run[0].start, run[len(run)-1].end, c.contextSize))
+		}
+		c.lock.Unlock()
+	}
+}
+
+// 判断分词是否为词典中没有的单字，标点、空白、英文和数字除外
+func isUnknownChar(ts *TagSet, token *Token) bool {
+	if !ts.Is(token.pos, TagUnknown) || len(token.text) != 1 || utf8.RuneCount(token.text[0]) != 1 {
+		return false
+	}
+	r, _ := utf8.DecodeRune(token.text[0])
+	return unicode.IsLetter(r) && r >= utf8.RuneSelf
+}
+
+// 返回runes[start:end]及其左右各size个字，中间部分用"[]"标出
+func unknownContext(runes []rune, start, end, size int) string {
+	start, end = minInt(start, len(runes)), minInt(end, len(runes))
+	return string(runes[maxInt(0, start-size):start]) + "[" + string(runes[start:end]) + "]" +
+		string(runes[end:minInt(len(runes), end+size)])
+}
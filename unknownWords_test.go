@@ -0,0 +1,100 @@
+package sego
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestUnknownSegmenter(t *testing.T, collector *UnknownWordCollector) *Segmenter {
+	t.Helper()
+	seg := new(Segmenter)
+	seg.LoadDictionary(writeTestFile(t, t.TempDir(), "dict.txt",
+		"今天 100 t",
+		"在 100 p",
+		"开会 100 v",
+		"和 100 c",
+	))
+	seg.SetUnknownWordCollector(collector)
+	return seg
+}
+
+func TestUnknownWordCollector(t *testing.T) {
+	collector := NewUnknownWordCollector()
+	seg := newTestUnknownSegmenter(t, collector)
+	for _, text := range []string{
+		"今天在鄂尔多斯开会",
+		// 单个未登录字不收集
+		"鄂尔多斯和鑫开会",
+		// 英文、空白和标点不计入，并把单字串隔开
+		"abc 鄂尔多斯，今天",
+		"鄂尔，多斯",
+	} {
+		seg.Segment([]byte(text))
+	}
+
+	want := []UnknownWord{
+		{"鄂尔多斯", 3, []string{"今天在[鄂尔多斯]开会", "[鄂尔多斯]和鑫开会", "abc [鄂尔多斯]，今天"}},
+		{"多斯", 1, []string{"鄂尔，[多斯]"}},
+		{"鄂尔", 1, []string{"[鄂尔]，多斯"}},
+	}
+	if got := collector.Words(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Words(1) = %v, want %v", got, want)
+	}
+	if got := collector.Words(2); !reflect.DeepEqual(got, want[:1]) {
+		t.Errorf("Words(2) = %v, want %v", got, want[:1])
+	}
+	entries := collector.Entries(2, "ns")
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0], DictionaryEntry{Text: "鄂尔多斯", Tags: []TokenPos{{"ns", 3}}}) {
+		t.Errorf("Entries(2, ns) = %v", entries)
+	}
+
+	if !collector.Remove("鄂尔") || collector.Remove("鄂尔") {
+		t.Error("Remove(鄂尔) should succeed once")
+	}
+	collector.Reset()
+	if got := collector.Words(1); len(got) != 0 {
+		t.Errorf("Words(1) after Reset() = %v", got)
+	}
+}
+
+func TestUnknownWordCollectorLimits(t *testing.T) {
+	collector := NewUnknownWordCollector()
+	seg := newTestUnknownSegmenter(t, collector)
+
+	// 超过最大字数的单字串不收集，上下文只记录第一处、左右各2个字
+	collector.SetLengthRange(2, 3)
+	collector.SetContexts(1, 2)
+	seg.Segment([]byte("今天在鄂尔多斯开会"))
+	seg.Segment([]byte("今天在鄂尔开会"))
+	seg.Segment([]byte("和鄂尔"))
+	want := []UnknownWord{{"鄂尔", 2, []string{"天在[鄂尔]开会"}}}
+	if got := collector.Words(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Words(1) = %v, want %v", got, want)
+	}
+
+	// 达到上限后不再收集新的单字串，已有的继续计数
+	collector.SetMaxWords(1)
+	seg.Segment([]byte("多斯在鄂尔"))
+	want[0].Count = 3
+	if got := collector.Words(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("Words(1) after SetMaxWords(1) = %v, want %v", got, want)
+	}
+}
+
+func TestUnknownWordCollectorTagSet(t *testing.T) {
+	collector := NewUnknownWordCollector()
+	seg := newTestUnknownSegmenter(t, collector)
+
+	// 标注集中"x"不属于TagUnknown类别时不收集
+	seg.SetTagSet(NewTagSet("custom", Tag{"x", "其它", TagOther}))
+	seg.Segment([]byte("今天在鄂尔多斯开会"))
+	if got := collector.Words(1); len(got) != 0 {
+		t.Errorf("Words(1) = %v, want none", got)
+	}
+
+	seg.SetTagSet(ICTCLASTagSet)
+	seg.Segment([]byte("今天在鄂尔多斯开会"))
+	if got := collector.Words(1); len(got) != 1 || got[0].Text != "鄂尔多斯" {
+		t.Errorf("Words(1) with ICTCLASTagSet = %v, want [鄂尔多斯]", got)
+	}
+}
@@ -0,0 +1,166 @@
+package sego
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// 把分词结果以"分词/词性 "的格式写入w，输出与SegmentsToString相同
+func WriteSegments(w io.Writer, segs []Segment, searchMode bool) error {
+	buf := bufio.NewWriter(w)
+	for i := range segs {
+		if searchMode {
+			writeToken(buf, segs[i].token, segs[i].Pos())
+		} else {
+			writeWordPos(buf, segs[i].token.text, segs[i].Pos())
+			buf.WriteByte(' ')
+		}
+	}
+	return buf.Flush()
+}
+
+// 按tokenToSlice的顺序写出分词及其子分词，pos为分词本身的词性
+func writeToken(buf *bufio.Writer, token *Token, pos string) {
+	hasOnlyTerminalToken := true
+	for _, s := range token.segments {
+		if len(s.token.segments) > 1 {
+			hasOnlyTerminalToken = false
+		}
+	}
+
+	if !hasOnlyTerminalToken {
+		for _, s := range token.segments {
+			if s != nil {
+				writeToken(buf, s.token, s.token.pos)
+			}
+		}
+	}
+	writeWordPos(buf, token.text, pos)
+	buf.WriteByte(' ')
+}
+
+func writeWordPos(buf *bufio.Writer, text []Text, pos string) {
+	for _, word := range text {
+		buf.Write(word)
+	}
+	buf.WriteByte('/')
+	buf.WriteString(pos)
+}
+
+// 把分词结果写成语料格式的一行，分词之间用一个空格分隔，比如
+//	迈向/v 充满/v 希望/n 的/u 新/a 世纪/n
+// 空白分词不输出，这种格式可以被PosTagger.Train读入。
+func WriteCorpus(w io.Writer, segs []Segment) error {
+	buf := bufio.NewWriter(w)
+	first := true
+	for i := range segs {
+		if symbolKind(segs[i].token) == symbolWhitespace {
+			continue
+		}
+		if !first {
+			buf.WriteByte(' ')
+		}
+		writeWordPos(buf, segs[i].token.text, segs[i].Pos())
+		first = false
+	}
+	buf.WriteByte('\n')
+	return buf.Flush()
+}
+
+// JSON输出中的一个分词
+type jsonSegment struct {
+	Text   string   `json:"text"`
+	Pos    string   `json:"pos"`
+	Start  int      `json:"start"`
+	End    int      `json:"end"`
+	Pinyin []string `json:"pinyin,omitempty"`
+}
+
+// 把分词结果写成一行JSON数组，每个分词为一个包含text、pos、start和end的对象，
+// 设置了拼音词典时还包括pinyin。每次调用输出一行，适合逐篇写出JSON Lines文件。
+func WriteJSONLine(w io.Writer, segs []Segment) error {
+	output := make([]jsonSegment, len(segs))
+	for i := range segs {
+		output[i] = jsonSegment{
+			Text:   segs[i].token.Text(),
+			Pos:    segs[i].Pos(),
+			Start:  segs[i].start,
+			End:    segs[i].end,
+			Pinyin: segs[i].Pinyin(),
+		}
+	}
+	// Encoder在每个值之后写出换行符
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(output)
+}
+
+// 把分词结果写成CoNLL-U格式的一个句子，以空行结束
+//
+// FORM为分词文本，XPOS为原始词性，UPOS由词性首字母映射到Universal Dependencies
+// 的词性，无法映射时为"X"。MISC列记录分词的起止位置和SpaceAfter=No，空白分词不输出。
+// 其余列为"_"。
+func WriteCoNLLU(w io.Writer, segs []Segment) error {
+	buf := bufio.NewWriter(w)
+	buf.WriteString("# text = ")
+	buf.WriteString(strings.Replace(segmentArrayToStr(segs), "\n", " ", -1))
+	buf.WriteByte('\n')
+
+	id := 0
+	for i := range segs {
+		if symbolKind(segs[i].token) == symbolWhitespace {
+			continue
+		}
+		id++
+		pos := segs[i].Pos()
+		upos := universalPos(pos)
+		if symbolKind(segs[i].token) == symbolPunctuation {
+			upos = "PUNCT"
+		}
+		misc := "TokenRange=" + strconv.Itoa(segs[i].start) + ":" + strconv.Itoa(segs[i].end)
+		if i+1 < len(segs) && symbolKind(segs[i+1].token) != symbolWhitespace {
+			misc += "|SpaceAfter=No"
+		}
+		buf.WriteString(strings.Join([]string{
+			strconv.Itoa(id), segs[i].token.Text(), "_", upos, conllField(pos),
+			"_", "_", "_", "_", misc,
+		}, "\t"))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return buf.Flush()
+}
+
+func conllField(s string) string {
+	if s == "" {
+		return "_"
+	}
+	return s
+}
+
+// 词性首字母到Universal Dependencies词性的映射
+var universalPosPrefixes = map[byte]string{
+	'a': "ADJ", 'b': "ADJ", 'c': "CCONJ", 'd': "ADV", 'e': "INTJ", 'f': "ADP",
+	'h': "PART", 'k': "PART", 'm': "NUM", 'n': "NOUN", 'o': "INTJ", 'p': "ADP",
+	'q': "NOUN", 'r': "PRON", 's': "NOUN", 't': "NOUN", 'u': "PART", 'v': "VERB",
+	'w': "PUNCT", 'x': "X", 'y': "PART", 'z': "ADJ",
+}
+
+// 返回词性对应的Universal Dependencies词性
+func universalPos(pos string) string {
+	switch pos {
+	case "nr", "ns", "nt", "nz", "nrf", "nrj", "nsf":
+		return "PROPN"
+	case "vn":
+		return "NOUN"
+	}
+	if pos != "" {
+		if upos, ok := universalPosPrefixes[strings.ToLower(pos)[0]]; ok {
+			return upos
+		}
+	}
+	return "X"
+}